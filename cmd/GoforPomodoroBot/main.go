@@ -19,8 +19,14 @@ import (
 	"GoforPomodoro/internal/botmodule"
 	"GoforPomodoro/internal/data"
 	"GoforPomodoro/internal/data/persistence"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/messenger"
+	"GoforPomodoro/internal/sessionmanager"
 	"fmt"
 	"log"
+
+	// Chats can set any IANA timezone, even if the host has no tz database.
+	_ "time/tzdata"
 )
 
 func main() {
@@ -48,6 +54,11 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	appState.Scheduler = sessionmanager.NewScheduler(appState.Clock)
+	appState.SessionLimits = settings.SessionLimits
+	if err := data.LoadLocales(appState); err != nil {
+		log.Printf("[main] Could not load the translations in %s: %v\n", data.LocalesDir, err)
+	}
 
 	fmt.Printf("Hello from Go for Pomodoro!\n\n(debug mode set to: %v)\n\n", debugMode)
 
@@ -63,5 +74,39 @@ func main() {
 	}
 
 	// Start the actual bot
-	botmodule.CommandMenuLoop(settings, appVariables, appState)
+	bot, err := messenger.NewTelegram(settings.ApiToken, debugMode)
+	if err != nil {
+		log.Panic(err)
+	}
+	settings.BotName = bot.UserName()
+	log.Printf("Authorized on account %s", bot.UserName())
+
+	var updateSource messenger.UpdateSource
+	switch settings.UpdateMode {
+	case "", domain.UpdateModePolling:
+		updateSource = bot
+	case domain.UpdateModeWebhook:
+		if settings.ListenPortPublic == 0 {
+			log.Fatal("[main] ListenPortPublic is required in webhook mode")
+		}
+		webhook, err := bot.NewWebhook(settings.WebhookURL, settings.WebhookSecret)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go botmodule.ListenPublicHTTP(
+			settings.ListenAddressPublic,
+			settings.ListenPortPublic,
+			webhook,
+		)
+		if err := webhook.Register(); err != nil {
+			log.Fatalf("[main] Could not register the webhook: %v", err)
+		}
+		log.Printf("[main] Receiving the updates at %s", settings.WebhookURL)
+		updateSource = webhook
+	default:
+		log.Fatalf("[main] Unknown UpdateMode %q: use %q or %q",
+			settings.UpdateMode, domain.UpdateModePolling, domain.UpdateModeWebhook)
+	}
+
+	botmodule.CommandMenuLoop(settings, appVariables, appState, bot, updateSource)
 }
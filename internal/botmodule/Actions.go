@@ -18,7 +18,12 @@ package botmodule
 import (
 	"GoforPomodoro/internal/data"
 	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/inputprocess"
 	"GoforPomodoro/internal/sessionmanager"
+	"GoforPomodoro/internal/utils"
+	"log"
+	"strconv"
+	"strings"
 )
 
 func ActionRestoreSprint(
@@ -31,11 +36,45 @@ func ActionRestoreSprint(
 		appState,
 		chatId,
 		session,
-		communicator.RestBeginHandler,
-		communicator.RestFinishedHandler,
-		communicator.SessionFinishedHandler,
-		communicator.SessionPausedHandler,
+		communicator.SessionHandlers(),
 	)
+	communicator.StartLiveStatus(session)
+}
+
+func ActionPauseSprint(
+	senderId domain.ChatID,
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+) error {
+	session := data.GetUserSessionRunning(appState, chatId, senderId)
+	err := sessionmanager.PauseSession(session)
+	communicator.SessionPaused(err, *session)
+	return err
+}
+
+func ActionSkipPhase(
+	senderId domain.ChatID,
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+) error {
+	session := data.GetUserSessionRunning(appState, chatId, senderId)
+	err := sessionmanager.SkipPhase(session)
+	communicator.PhaseSkipped(err)
+	return err
+}
+
+func ActionNextSprint(
+	senderId domain.ChatID,
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+) error {
+	session := data.GetUserSessionRunning(appState, chatId, senderId)
+	err := sessionmanager.AdvanceSession(session)
+	communicator.NextSprint(err)
+	return err
 }
 
 func ActionCancelSprint(
@@ -43,18 +82,22 @@ func ActionCancelSprint(
 	chatId domain.ChatID,
 	appState *domain.AppState,
 	communicator *Communicator,
-) {
+) error {
 	session := data.GetUserSessionRunning(appState, chatId, senderId)
 
 	var err error
 	if !session.IsPaused() {
 		err = sessionmanager.CancelSession(session)
 	} else {
-		session.Cancel()
-		communicator.SessionFinishedHandler(chatId, session, sessionmanager.PomodoroCanceled)
+		err = sessionmanager.CancelPausedSession(appState, chatId, session)
+		if err == nil {
+			communicator.SessionFinishedHandler(chatId, session, sessionmanager.PomodoroCanceled)
+			data.UpdateUserSessionRunning(appState, chatId)
+		}
 	}
 
 	communicator.SessionCanceled(err, *session)
+	return err
 }
 
 func ActionResumeSprint(
@@ -62,20 +105,16 @@ func ActionResumeSprint(
 	chatId domain.ChatID,
 	appState *domain.AppState,
 	communicator *Communicator,
-) {
+) error {
 	session := data.GetUserSessionRunning(appState, chatId, senderId)
-	communicator.SessionResumed(
-		sessionmanager.ResumeSession(
-			appState,
-			chatId,
-			session,
-			communicator.RestBeginHandler,
-			communicator.RestFinishedHandler,
-			communicator.SessionFinishedHandler,
-			communicator.SessionPausedHandler,
-		),
+	err := sessionmanager.ResumeSession(
+		appState,
+		chatId,
 		session,
+		communicator.SessionHandlers(),
 	)
+	communicator.SessionResumed(err, session)
+	return err
 }
 
 func ActionStartSprint(
@@ -103,10 +142,222 @@ func ActionStartSprint(
 			appState,
 			chatId,
 			session,
-			communicator.RestBeginHandler,
-			communicator.RestFinishedHandler,
-			communicator.SessionFinishedHandler,
-			communicator.SessionPausedHandler,
+			communicator.SessionHandlers(),
 		),
 	)
 }
+
+func ActionSetSession(
+	senderId domain.ChatID,
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+	sessionData domain.SessionDefaultData,
+) {
+	_, err := inputprocess.ValidateSessionParsed(sessionData, data.GetSessionLimits(appState, chatId))
+	if err != nil {
+		communicator.SessionLimitError(err)
+		return
+	}
+
+	data.UpdateDefaultUserSession(appState, chatId, senderId, sessionData)
+
+	// Flow mode is a choice of the chat, not part of the session pattern.
+	sessionData.Flow = data.IsFlowEnabled(appState, chatId)
+	communicator.NewSession(sessionData)
+	autorun := data.GetUserAutorun(appState, chatId, senderId)
+	if autorun {
+		ActionStartSprint(senderId, chatId, appState, communicator)
+	}
+}
+
+// ActionStartDefaultSession Start the default preset of the chat, or the
+// classic 4x25m+5m session if the chat has none.
+func ActionStartDefaultSession(
+	senderId domain.ChatID,
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+) {
+	sessionData := domain.DefaultSession()
+
+	preset, err := data.GetDefaultPreset(appState, chatId).GetValue()
+	if err == nil {
+		presetData, err := inputprocess.ParsePresetPattern(preset.Pattern)
+		if err == nil {
+			sessionData = presetData
+		} else {
+			log.Printf("[ActionStartDefaultSession] invalid default preset %q: %v\n", preset.Name, err.Error())
+		}
+	}
+
+	if _, err := inputprocess.ValidateSessionParsed(sessionData, data.GetSessionLimits(appState, chatId)); err != nil {
+		communicator.SessionLimitError(err)
+		return
+	}
+
+	data.UpdateDefaultUserSession(appState, chatId, senderId, sessionData)
+	ActionStartSprint(senderId, chatId, appState, communicator)
+}
+
+// ActionPreset Manage the presets of the chat: save, list, delete, set the
+// default one, or start the one with the given name.
+func ActionPreset(
+	senderId domain.ChatID,
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+	parameters []string,
+) {
+	if len(parameters) == 0 {
+		communicator.PresetUsage()
+		return
+	}
+
+	switch strings.ToLower(parameters[0]) {
+	case "save":
+		if len(parameters) < 3 {
+			communicator.PresetUsage()
+			return
+		}
+		name := strings.ToLower(parameters[1])
+		if !domain.IsValidPresetName(name) || isPresetSubcommand(name) {
+			communicator.PresetInvalidName(name)
+			return
+		}
+		pattern := strings.Join(parameters[2:], " ")
+		sessionData, err := inputprocess.ParsePresetPattern(pattern)
+		if err != nil {
+			communicator.PresetError(err)
+			return
+		}
+		if _, err := inputprocess.ValidateSessionParsed(sessionData, data.GetSessionLimits(appState, chatId)); err != nil {
+			communicator.SessionLimitError(err)
+			return
+		}
+
+		// Overwriting a preset keeps it as the default one.
+		isDefault := false
+		if previous, err := data.GetPreset(appState, chatId, name); err == nil {
+			isDefault = previous.IsDefault
+		}
+
+		err = data.StorePreset(appState, chatId, domain.Preset{
+			Name:      name,
+			Pattern:   pattern,
+			IsDefault: isDefault,
+		})
+		communicator.PresetSaved(err, name, sessionData)
+	case "list":
+		presets, err := data.GetPresets(appState, chatId)
+		communicator.PresetList(err, presets)
+	case "delete":
+		if len(parameters) < 2 {
+			communicator.PresetUsage()
+			return
+		}
+		name := strings.ToLower(parameters[1])
+		communicator.PresetDeleted(data.DeletePreset(appState, chatId, name), name)
+	case "default":
+		if len(parameters) < 2 {
+			communicator.PresetUsage()
+			return
+		}
+		name := strings.ToLower(parameters[1])
+		preset, err := data.GetPreset(appState, chatId, name)
+		if err == nil {
+			preset.IsDefault = true
+			err = data.StorePreset(appState, chatId, preset)
+		}
+		communicator.PresetDefaultSet(err, name)
+	default:
+		name := strings.ToLower(parameters[0])
+		preset, err := data.GetPreset(appState, chatId, name)
+		if err != nil {
+			communicator.PresetError(err)
+			return
+		}
+		sessionData, err := inputprocess.ParsePresetPattern(preset.Pattern)
+		if err != nil {
+			communicator.PresetError(err)
+			return
+		}
+		// The limits of the chat may have changed since the preset was saved.
+		if _, err := inputprocess.ValidateSessionParsed(sessionData, data.GetSessionLimits(appState, chatId)); err != nil {
+			communicator.SessionLimitError(err)
+			return
+		}
+		data.UpdateDefaultUserSession(appState, chatId, senderId, sessionData)
+		communicator.NewSession(sessionData)
+		ActionStartSprint(senderId, chatId, appState, communicator)
+	}
+}
+
+// isPresetSubcommand returns true if the name is reserved to a /preset
+// subcommand.
+func isPresetSubcommand(name string) bool {
+	return utils.Contains([]string{"save", "list", "delete", "default"}, name)
+}
+
+// ActionLimits Show the session limits of the chat or, for the admins of the
+// bot, override (/limits set <name> <value>) or restore them, one at a time
+// (/limits unset <name>) or all at once (/limits reset).
+func ActionLimits(
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+	parameters []string,
+	isAdmin bool,
+) {
+	if len(parameters) == 0 || parameters[0] == "" {
+		communicator.SessionLimits(data.GetSessionLimits(appState, chatId))
+		return
+	}
+
+	if !isAdmin {
+		communicator.LimitsAdminOnly()
+		return
+	}
+
+	switch strings.ToLower(parameters[0]) {
+	case "set":
+		if len(parameters) != 3 {
+			communicator.LimitsUsage()
+			return
+		}
+		limit, ok := domain.ParseSessionLimit(strings.ToLower(parameters[1]))
+		if !ok {
+			communicator.LimitsUsage()
+			return
+		}
+		// 0 would unset the limit: /limits unset does it explicitly.
+		value, err := strconv.Atoi(parameters[2])
+		if err != nil || value < 1 {
+			communicator.LimitsUsage()
+			return
+		}
+		override := appState.ReadSettings(chatId).LimitsOverride
+		override.Set(limit, value)
+		data.SetLimitsOverride(appState, chatId, override)
+		communicator.SessionLimits(data.GetSessionLimits(appState, chatId))
+	case "unset":
+		if len(parameters) != 2 {
+			communicator.LimitsUsage()
+			return
+		}
+		limit, ok := domain.ParseSessionLimit(strings.ToLower(parameters[1]))
+		if !ok {
+			communicator.LimitsUsage()
+			return
+		}
+		override := appState.ReadSettings(chatId).LimitsOverride
+		override.Set(limit, 0)
+		data.SetLimitsOverride(appState, chatId, override)
+		communicator.SessionLimits(data.GetSessionLimits(appState, chatId))
+	case "reset":
+		data.SetLimitsOverride(appState, chatId, domain.SessionLimits{})
+		communicator.SessionLimits(data.GetSessionLimits(appState, chatId))
+	default:
+		communicator.LimitsUsage()
+	}
+}
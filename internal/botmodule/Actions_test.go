@@ -0,0 +1,102 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/messenger"
+	"testing"
+)
+
+func TestStoredSessionsCheckLimits(t *testing.T) {
+	english := i18n.English()
+	expected := english.T("limit_sprints_exceeded", english.N("pomodoros", 2), 4) + english.T("limit_see_limits")
+
+	cases := []struct {
+		name  string
+		start func(appState *domain.AppState, communicator *Communicator)
+	}{
+		{"preset", func(appState *domain.AppState, communicator *Communicator) {
+			ActionPreset(testUser, testUser, appState, communicator, []string{"long"})
+		}},
+		{"default preset", func(appState *domain.AppState, communicator *Communicator) {
+			ActionPreset(testUser, testUser, appState, communicator, []string{"default", "long"})
+			ActionStartDefaultSession(testUser, testUser, appState, communicator)
+		}},
+		{"default session", func(appState *domain.AppState, communicator *Communicator) {
+			ActionStartDefaultSession(testUser, testUser, appState, communicator)
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			bot := messenger.NewRecorder()
+			appState := newTestAppState()
+			communicator := GetCommunicator(appState, &domain.AppVariables{}, testUser, bot)
+
+			ActionPreset(testUser, testUser, appState, communicator, []string{"save", "long", "25for4"})
+			// The limits changed after the preset was saved.
+			data.SetLimitsOverride(appState, testUser, domain.SessionLimits{MaxSprints: 2})
+
+			c.start(appState, communicator)
+
+			if got := lastText(bot, testUser); got != expected {
+				t.Errorf("the limits should be reported: %q", got)
+			}
+			if session := data.GetUserSessionRunning(appState, testUser, testUser); session.State() != domain.StatePending {
+				t.Errorf("the session should not have started: %v", session.State())
+			}
+		})
+	}
+}
+
+func TestActionLimits(t *testing.T) {
+	bot := messenger.NewRecorder()
+	appState := newTestAppState()
+	communicator := GetCommunicator(appState, &domain.AppVariables{}, testUser, bot)
+	english := i18n.English()
+
+	ActionLimits(testUser, appState, communicator, []string{"set", "sprint", "90"}, true)
+	ActionLimits(testUser, appState, communicator, []string{"set", "sprints", "20"}, true)
+	if limits := data.GetSessionLimits(appState, testUser); limits.MaxSprintMinutes != 90 || limits.MaxSprints != 20 {
+		t.Fatalf("the limits should be overridden: %+v", limits)
+	}
+
+	ActionLimits(testUser, appState, communicator, []string{"set", "sprint", "0"}, true)
+	if got := lastText(bot, testUser); got != english.T("limits_usage") {
+		t.Errorf("0 should not be accepted: %q", got)
+	}
+	if limits := data.GetSessionLimits(appState, testUser); limits.MaxSprintMinutes != 90 {
+		t.Errorf("a rejected value should not change the limit: %+v", limits)
+	}
+
+	ActionLimits(testUser, appState, communicator, []string{"unset", "sprint"}, true)
+	limits := data.GetSessionLimits(appState, testUser)
+	if limits.MaxSprintMinutes != domain.DefaultSessionLimits.MaxSprintMinutes || limits.MaxSprints != 20 {
+		t.Errorf("only the longest pomodoro should be unset: %+v", limits)
+	}
+
+	ActionLimits(testUser, appState, communicator, []string{"reset"}, true)
+	if limits := data.GetSessionLimits(appState, testUser); limits != domain.DefaultSessionLimits {
+		t.Errorf("all the limits should be reset: %+v", limits)
+	}
+
+	ActionLimits(testUser, appState, communicator, []string{"unset", "sprint"}, false)
+	if got := lastText(bot, testUser); got != english.T("limits_admin_only") {
+		t.Errorf("only the admins can change the limits: %q", got)
+	}
+}
@@ -0,0 +1,210 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/messenger"
+	"GoforPomodoro/internal/sessionmanager"
+	"log"
+)
+
+// Callback data of the buttons of the session messages.
+const (
+	callbackStatus = "act:status"
+	callbackPause  = "act:pause"
+	callbackResume = "act:resume"
+	callbackSkip   = "act:skip"
+	callbackCancel = "act:cancel"
+	callbackNext   = "act:next"
+
+	// callbackHourglass is the payload of the only button of the messages
+	// sent before the session controls existed.
+	callbackHourglass = "⌛"
+)
+
+// sessionKeyboard Build the buttons that control a session in the given state.
+func sessionKeyboard(l *i18n.Localizer, state domain.SessionState) messenger.Keyboard {
+	statusRow := messenger.NewRow(
+		messenger.Button{Text: l.T("button_status"), Data: callbackStatus},
+	)
+
+	switch {
+	case state == domain.StateReady:
+		return messenger.NewKeyboard(
+			messenger.NewRow(
+				messenger.Button{Text: l.T("button_next"), Data: callbackNext},
+			),
+			messenger.NewRow(
+				messenger.Button{Text: l.T("button_pause"), Data: callbackPause},
+				messenger.Button{Text: l.T("button_cancel"), Data: callbackCancel},
+			),
+			statusRow,
+		)
+	case state == domain.StateOvertime:
+		return messenger.NewKeyboard(
+			messenger.NewRow(
+				messenger.Button{Text: l.T("button_pause"), Data: callbackPause},
+				messenger.Button{Text: l.T("button_break"), Data: callbackSkip},
+				messenger.Button{Text: l.T("button_cancel"), Data: callbackCancel},
+			),
+			statusRow,
+		)
+	case state.IsRunning():
+		return messenger.NewKeyboard(
+			messenger.NewRow(
+				messenger.Button{Text: l.T("button_pause"), Data: callbackPause},
+				messenger.Button{Text: l.T("button_skip"), Data: callbackSkip},
+				messenger.Button{Text: l.T("button_cancel"), Data: callbackCancel},
+			),
+			statusRow,
+		)
+	case state.IsPaused() && state != domain.StatePending:
+		return messenger.NewKeyboard(
+			messenger.NewRow(
+				messenger.Button{Text: l.T("button_resume"), Data: callbackResume},
+				messenger.Button{Text: l.T("button_cancel"), Data: callbackCancel},
+			),
+			statusRow,
+		)
+	default:
+		return messenger.NewKeyboard(statusRow)
+	}
+}
+
+// callbackContext is what a callbackHandler needs to act on a button press.
+type callbackContext struct {
+	appState     *domain.AppState
+	chatId       domain.ChatID
+	senderId     domain.ChatID
+	communicator *Communicator
+}
+
+// callbackHandler Act on a button press. It returns the text of the toast
+// shown to the user and the state the session is (or is about to be) in.
+type callbackHandler func(ctx callbackContext) (string, domain.SessionState)
+
+var callbackHandlers = map[string]callbackHandler{
+	callbackStatus:    statusCallback,
+	callbackHourglass: statusCallback,
+	callbackPause:     pauseCallback,
+	callbackResume:    resumeCallback,
+	callbackSkip:      skipCallback,
+	callbackCancel:    cancelCallback,
+	callbackNext:      nextCallback,
+}
+
+func statusCallback(ctx callbackContext) (string, domain.SessionState) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	return session.LeftTimeMessage(ctx.communicator.Localizer()), session.State()
+}
+
+func pauseCallback(ctx callbackContext) (string, domain.SessionState) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	if err := ActionPauseSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator); err != nil {
+		return session.LeftTimeMessage(ctx.communicator.Localizer()), session.State()
+	}
+
+	// The session is paused by its timer goroutine, which may not have done
+	// it yet.
+	if session.IsRest() {
+		return ctx.communicator.Localizer().T("toast_paused"), domain.StatePausedRest
+	}
+	return ctx.communicator.Localizer().T("toast_paused"), domain.StatePausedSprint
+}
+
+func resumeCallback(ctx callbackContext) (string, domain.SessionState) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	// Once resumed, the session belongs to its timer goroutine again.
+	next, _ := session.ResumedState()
+	if err := ActionResumeSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator); err != nil {
+		return session.LeftTimeMessage(ctx.communicator.Localizer()), session.State()
+	}
+	return ctx.communicator.Localizer().T("toast_resumed"), next
+}
+
+func skipCallback(ctx callbackContext) (string, domain.SessionState) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	// The phase is skipped by the timer goroutine of the session, which may
+	// not have done it yet.
+	next := sessionmanager.SkippedState(ctx.appState, ctx.chatId, session)
+	if err := ActionSkipPhase(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator); err != nil {
+		return session.LeftTimeMessage(ctx.communicator.Localizer()), session.State()
+	}
+	return ctx.communicator.Localizer().T("toast_skipped"), next
+}
+
+func nextCallback(ctx callbackContext) (string, domain.SessionState) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	if err := ActionNextSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator); err != nil {
+		return session.LeftTimeMessage(ctx.communicator.Localizer()), session.State()
+	}
+
+	// The sprint is started by the timer goroutine of the session, which may
+	// not have done it yet.
+	return ctx.communicator.Localizer().T("toast_started"), domain.StateSprint
+}
+
+func cancelCallback(ctx callbackContext) (string, domain.SessionState) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	if err := ActionCancelSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator); err != nil {
+		return session.LeftTimeMessage(ctx.communicator.Localizer()), session.State()
+	}
+	return ctx.communicator.Localizer().T("toast_canceled"), domain.StateCanceled
+}
+
+// DispatchCallback Route a button press to its callbackHandler, answer it
+// with a toast and update the buttons of the message to the new state of the
+// session.
+func DispatchCallback(
+	appState *domain.AppState,
+	appVariables *domain.AppVariables,
+	bot messenger.Messenger,
+	query *messenger.Callback,
+) {
+	handler, ok := callbackHandlers[query.Data]
+	if !ok || query.MessageID == 0 {
+		log.Printf("[DispatchCallback] unknown callback data: %q\n", query.Data)
+		if err := bot.AnswerCallback(query.ID, ""); err != nil {
+			log.Println("[ERROR] " + err.Error())
+		}
+		return
+	}
+
+	chatId := query.ChatID
+	ctx := callbackContext{
+		appState:     appState,
+		chatId:       chatId,
+		senderId:     query.SenderID,
+		communicator: GetCommunicator(appState, appVariables, chatId, bot),
+	}
+
+	toastText, state := handler(ctx)
+	if err := bot.AnswerCallback(query.ID, toastText); err != nil {
+		log.Println("[ERROR] " + err.Error())
+	}
+
+	// Telegram refuses edits that leave the message unchanged.
+	keyboard := sessionKeyboard(ctx.communicator.Localizer(), state)
+	if query.Keyboard.Equal(keyboard) {
+		return
+	}
+	if err := bot.EditKeyboard(chatId, query.MessageID, keyboard); err != nil {
+		log.Println("[ERROR] " + err.Error())
+	}
+}
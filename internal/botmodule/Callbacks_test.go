@@ -0,0 +1,148 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/messenger"
+	"testing"
+)
+
+// keyboardData returns the callback data of the buttons, row by row.
+func keyboardData(keyboard messenger.Keyboard) [][]string {
+	var rows [][]string
+	for _, row := range keyboard {
+		var data []string
+		for _, button := range row {
+			data = append(data, button.Data)
+		}
+		rows = append(rows, data)
+	}
+	return rows
+}
+
+func TestSessionKeyboard(t *testing.T) {
+	tests := []struct {
+		state    domain.SessionState
+		expected [][]string
+	}{
+		{domain.StatePending, [][]string{{callbackStatus}}},
+		{domain.StateSprint, [][]string{{callbackPause, callbackSkip, callbackCancel}, {callbackStatus}}},
+		{domain.StateRest, [][]string{{callbackPause, callbackSkip, callbackCancel}, {callbackStatus}}},
+		{domain.StateOvertime, [][]string{{callbackPause, callbackSkip, callbackCancel}, {callbackStatus}}},
+		{domain.StateReady, [][]string{{callbackNext}, {callbackPause, callbackCancel}, {callbackStatus}}},
+		{domain.StatePausedSprint, [][]string{{callbackResume, callbackCancel}, {callbackStatus}}},
+		{domain.StatePausedRest, [][]string{{callbackResume, callbackCancel}, {callbackStatus}}},
+		{domain.StateCanceled, [][]string{{callbackStatus}}},
+		{domain.StateFinished, [][]string{{callbackStatus}}},
+	}
+	for _, test := range tests {
+		got := keyboardData(sessionKeyboard(i18n.English(), test.state))
+		if len(got) != len(test.expected) {
+			t.Errorf("%v: expected the buttons %v, got %v", test.state, test.expected, got)
+			continue
+		}
+		for i := range got {
+			if len(got[i]) != len(test.expected[i]) {
+				t.Errorf("%v: expected the buttons %v, got %v", test.state, test.expected, got)
+				break
+			}
+			for j := range got[i] {
+				if got[i][j] != test.expected[i][j] {
+					t.Errorf("%v: expected the buttons %v, got %v", test.state, test.expected, got)
+				}
+			}
+		}
+	}
+}
+
+func TestDispatchCallback(t *testing.T) {
+	start := func(session *domain.Session) error { return session.Start() }
+	rest := func(session *domain.Session) error {
+		if err := session.Start(); err != nil {
+			return err
+		}
+		return session.RestStarted()
+	}
+	then := func(first, second func(session *domain.Session) error) func(session *domain.Session) error {
+		return func(session *domain.Session) error {
+			if err := first(session); err != nil {
+				return err
+			}
+			return second(session)
+		}
+	}
+	pause := func(session *domain.Session) error { return session.Pause() }
+	ready := func(session *domain.Session) error { return session.ReadyStarted() }
+
+	tests := []struct {
+		name     string
+		sprints  domain.SprintDuration
+		manual   bool
+		prepare  func(session *domain.Session) error
+		button   string
+		expected domain.SessionState
+	}{
+		{"status", 4, false, start, callbackStatus, domain.StateSprint},
+		{"pause sprint", 4, false, start, callbackPause, domain.StatePausedSprint},
+		{"pause rest", 4, false, rest, callbackPause, domain.StatePausedRest},
+		{"resume sprint", 4, false, then(start, pause), callbackResume, domain.StateSprint},
+		{"resume rest", 4, false, then(rest, pause), callbackResume, domain.StateRest},
+		{"skip sprint", 4, false, start, callbackSkip, domain.StateRest},
+		{"skip last sprint", 1, false, start, callbackSkip, domain.StateFinished},
+		{"skip rest", 4, false, rest, callbackSkip, domain.StateSprint},
+		{"skip rest before waiting", 4, true, rest, callbackSkip, domain.StateReady},
+		{"next sprint", 4, true, then(rest, ready), callbackNext, domain.StateSprint},
+		{"cancel", 4, false, start, callbackCancel, domain.StateCanceled},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bot := messenger.NewRecorder()
+			appState := newTestAppState()
+			l := i18n.English()
+
+			sdd := domain.DefaultSession()
+			sdd.SprintDurationSet = test.sprints
+			data.UpdateDefaultUserSession(appState, testUser, testUser, sdd)
+			data.SetManualAdvance(appState, testUser, test.manual, 0)
+			session := data.GetUserSessionRunning(appState, testUser, testUser)
+			if err := test.prepare(session); err != nil {
+				t.Fatal(err)
+			}
+			keyboard := sessionKeyboard(l, session.State())
+			messageId, _ := bot.SendWithKeyboard(testUser, "session", keyboard)
+
+			DispatchCallback(appState, &domain.AppVariables{}, bot, &messenger.Callback{
+				ID:        "query",
+				ChatID:    testUser,
+				SenderID:  testUser,
+				MessageID: messageId,
+				Data:      test.button,
+				Keyboard:  keyboard,
+			})
+
+			if answers := bot.Answers(); len(answers) != 1 || answers[0].Text == "" {
+				t.Fatalf("the button press should be answered with a toast: %+v", answers)
+			}
+			expected := sessionKeyboard(l, test.expected)
+			if got := bot.Messages(testUser)[0].Keyboard; !got.Equal(expected) {
+				t.Fatalf("expected the buttons of %v, got %v", test.expected, keyboardData(got))
+			}
+		})
+	}
+}
@@ -19,15 +19,13 @@ import (
 	"GoforPomodoro/internal/data"
 	"GoforPomodoro/internal/domain"
 	"GoforPomodoro/internal/inputprocess"
-	"GoforPomodoro/internal/sessionmanager"
+	"GoforPomodoro/internal/messenger"
 	"GoforPomodoro/internal/utils"
 	"fmt"
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"strings"
 )
 
 /*
@@ -65,6 +63,18 @@ func ListenPrivateHTTP(appState *domain.AppState, address string, port int) {
 	}
 }
 
+// ListenPublicHTTP Serve the webhook of the bot. The listener has a mux of
+// its own, so that the endpoints of ListenPrivateHTTP are not made public.
+func ListenPublicHTTP(address string, port int, webhook *messenger.Webhook) {
+	mux := http.NewServeMux()
+	mux.Handle(webhook.Path(), webhook)
+
+	err := http.ListenAndServe(fmt.Sprintf("%s:%d", address, port), mux)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
 func getHello(w http.ResponseWriter, r *http.Request) {
 	_ = r
 	fmt.Printf("got /hello request\n")
@@ -74,51 +84,55 @@ func getHello(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// CommandMenuLoop Answer the messages and the button presses of the users,
+// until the updates end or the bot is shut down.
 func CommandMenuLoop(
 	settings *domain.AppSettings,
 	appVariables *domain.AppVariables,
 	appState *domain.AppState,
+	bot messenger.Messenger,
+	updateSource messenger.UpdateSource,
 ) {
-	bot, err := tgbotapi.NewBotAPI(settings.ApiToken)
-	if err != nil {
-		log.Panic(err)
-	}
-
-	settings.BotName = bot.Self.UserName
-
 	debugMode := settings.DebugMode
-	bot.Debug = debugMode
-
-	log.Printf("Authorized on account %s", bot.Self.UserName)
-
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
 
 	RestoreSessions(appState, appVariables, bot)
+	RestoreSchedules(appState)
 
 	PrivacyPolicyEnabled := appVariables.PrivacyPolicyEnabled
 	privacyVersion := appVariables.PrivacySettingsVersion
 
-	updates := bot.GetUpdatesChan(u)
+	registry := botCommandRegistry()
+	registry.publish(bot, appState.Messages, settings.AdminIds)
+
+	updates := updateSource.Updates()
 
 mainLoop:
-	for update := range updates {
+	for {
+		var update messenger.Update
+		select {
+		case schedule := <-appState.ScheduledStarts:
+			fireSchedule(appState, appVariables, bot, schedule)
+			continue
+		case nextUpdate, ok := <-updates:
+			if !ok {
+				break mainLoop
+			}
+			update = nextUpdate
+		}
+
 		if update.Message != nil { // If we got a message
-			senderId := domain.ChatID(update.Message.From.ID)
-			chatId := domain.ChatID(update.Message.Chat.ID)
+			senderId := update.Message.SenderID
+			chatId := update.Message.ChatID
 
 			newChat := data.IsThisNewUser(appState, chatId)
 
 			if debugMode {
-				log.Printf("[%s] %s\n", update.Message.From.UserName, update.Message.Text)
+				log.Printf("[%s] %s\n", update.Message.SenderUsername, update.Message.Text)
 				log.Printf("New chat? | %v\n", utils.YesNo(newChat))
 			}
 
 			msgText := update.Message.Text
 
-			// var replyMsg tgbotapi.MessageConfig
-			// var replyMsgText string
-
 			command := inputprocess.CommandFrom(settings, msgText)
 			parameters := inputprocess.ParametersFrom(msgText)
 
@@ -126,8 +140,10 @@ mainLoop:
 				log.Printf("command: %s\n", command)
 			}
 
-			isGroup := update.Message.Chat.IsGroup() || update.Message.Chat.IsSuperGroup()
+			isGroup := update.Message.IsGroup
 			data.AdjustChatType(appState, chatId, senderId, isGroup)
+			data.DetectLanguage(appState, chatId, update.Message.SenderLanguage)
+			isAdmin := utils.Contains(settings.AdminIds, senderId)
 
 			communicator := GetCommunicator(appState, appVariables, chatId, bot)
 
@@ -158,138 +174,33 @@ mainLoop:
 			} else {
 				if newChat {
 					communicator.Info()
-					communicator.Help()
+					communicator.Help(registry, isAdmin)
 					data.DefaultUserSettingsIfNeeded(appState, chatId)
 				}
 			}
 
-			switch command {
-			// Admin commands
-			case "/shutdown":
-				isAdmin := utils.Contains(settings.AdminIds, senderId)
-				if isAdmin {
-					communicator.ReplyWith("Soft shutting down...")
-					data.PrepareForShutdown(
-						appState,
-						func() {
-							communicator.ReplyWith("DB lock acquired.")
-							os.Exit(0)
-						},
-					)
-					break mainLoop
-				}
-			// Group commands
-			case "/join":
-				if !isGroup {
-					communicator.ReplyWith("This command works only in groups, sorry.")
-					continue
-				}
-				senderChat, err := bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: int64(senderId)}})
-				if err != nil {
-					communicator.ReplyWith("Error with your account.")
-					continue
-				}
-
-				communicator.Subscribe(
-					data.SubscribeUserInGroup(appState, chatId, senderId),
-					update,
-					senderChat.UserName,
-				)
-			case "/leave":
-				if !isGroup {
-					communicator.OnlyGroupsCommand()
-					continue
-				}
-
-				communicator.Unsubscribe(data.UnsubscribeUser(appState, chatId, senderId))
-			// Personal commands
-			case "/autorun":
-				if len(parameters) > 0 {
-					param := parameters[0]
-					var autorun bool
-					if param == "on" {
-						autorun = true
-					} else if param == "off" {
-						autorun = false
-					} else {
-						communicator.CommandError()
-						continue
-					}
-					data.SetUserAutorun(appState, chatId, senderId, autorun)
-					communicator.ReplyWith("Autorun set " + strings.ToUpper(param) + ".")
-				} else {
-					data.SetUserAutorun(appState, chatId, senderId, true)
-					communicator.ReplyWith("Autorun set ON.")
-				}
-			case "/se", "/session":
-				session := data.GetUserSessionRunning(appState, chatId, senderId)
-				communicator.SessionState(*session)
-			case "/p", "/pause":
-				session := data.GetUserSessionRunning(appState, chatId, senderId)
-				err := sessionmanager.PauseSession(session)
-				communicator.SessionPaused(err, *session)
-			case "/c", "/cancel":
-				ActionCancelSprint(senderId, chatId, appState, communicator)
-			case "/resume":
-				ActionResumeSprint(senderId, chatId, appState, communicator)
-			case "/d", "/default":
-				data.UpdateDefaultUserSession(appState, chatId, senderId, domain.DefaultSession())
-				ActionStartSprint(senderId, chatId, appState, communicator)
-			case "/s", "/start_sprint":
-				ActionStartSprint(senderId, chatId, appState, communicator)
-			case "/reset":
-				data.CleanUserSettings(appState, chatId, senderId)
-				communicator.DataCleaned()
-			case "/help":
-				communicator.Help()
-			case "/info":
-				communicator.Info()
-			case "/clessidra":
-				communicator.Hourglass()
-			default:
-				sessionDataOpt := inputprocess.ParsePatternToSession(nil, msgText)
-				sessionData, err := sessionDataOpt.GetValue()
-				if err != nil {
-					// Session wasn't parsed
-					continue
-				}
-				_, err = inputprocess.ValidateSessionParsed(sessionData)
-				if err == nil {
-					data.UpdateDefaultUserSession(appState, chatId, senderId, sessionData)
-					communicator.NewSession(sessionData)
-					autorun := data.GetUserAutorun(appState, chatId, senderId)
-					if autorun {
-						ActionStartSprint(senderId, chatId, appState, communicator)
-					}
-				} else {
-					communicator.ErrorSessionTooLong()
-				}
+			ctx := &commandContext{
+				settings:     settings,
+				appState:     appState,
+				bot:          bot,
+				chatId:       chatId,
+				senderId:     senderId,
+				isGroup:      isGroup,
+				isAdmin:      isAdmin,
+				parameters:   parameters,
+				communicator: communicator,
+				registry:     registry,
 			}
-		} else if update.CallbackQuery != nil {
-			// Respond to the callback query, telling Telegram to show the user
-			// a message with the data received.
-
-			switch update.CallbackQuery.Data {
-			case "⌛":
-				chatId := domain.ChatID(update.CallbackQuery.Message.Chat.ID)
-				senderId := domain.ChatID(update.CallbackQuery.Message.From.ID)
-
-				session := data.GetUserSessionRunning(appState, chatId, senderId)
-
-				// We reply with a toast (callback)
-				toastText := session.LeftTimeMessage()
-				callback := tgbotapi.NewCallback(update.CallbackQuery.ID, toastText)
-				if _, err := bot.Request(callback); err != nil {
-					log.Println("[ERROR] " + err.Error())
+			if cmd, ok := registry.lookup(command); ok {
+				registry.dispatch(cmd, ctx)
+				if ctx.stop {
+					break mainLoop
 				}
-
-				// To reply with a message
-				// sg := tgbotapi.NewMessage(update.CallbackQuery.Message.Chat.ID, update.CallbackQuery.Data)
-				// if _, err := bot.Send(msg); err != nil {
-				// 	   // manage error
-				// }
-
+			} else {
+				sessionPatternCommand(ctx, command)
 			}
+		} else if update.Callback != nil {
+			DispatchCallback(appState, appVariables, bot, update.Callback)
 		}
 	}
 }
@@ -0,0 +1,219 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/messenger"
+	"GoforPomodoro/internal/sessionmanager"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testUser  domain.ChatID = 42
+	testGroup domain.ChatID = -100
+)
+
+// newTestAppState returns the state of a bot with no persistence, whose
+// clock is stopped at 9:00.
+func newTestAppState() *domain.AppState {
+	clock := domain.NewFakeClock(time.Date(2022, time.October, 17, 9, 0, 0, 0, time.UTC))
+	return &domain.AppState{
+		Clock:           clock,
+		Scheduler:       sessionmanager.NewScheduler(clock),
+		UsersSettings:   make(map[domain.ChatID]*domain.Settings),
+		Messages:        i18n.NewBundle(),
+		ScheduledStarts: make(chan domain.SessionSchedule, domain.MaxPendingScheduledStarts),
+	}
+}
+
+// runCommandMenu Run CommandMenuLoop on the updates, returning the state of
+// the bot once they have all been handled.
+func runCommandMenu(t *testing.T, bot *messenger.Recorder, updates ...messenger.Update) *domain.AppState {
+	t.Helper()
+
+	appState := newTestAppState()
+	settings := &domain.AppSettings{BotName: "go4pom_bot", AdminIds: []domain.ChatID{1}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		CommandMenuLoop(settings, &domain.AppVariables{}, appState, bot, bot)
+	}()
+
+	for _, update := range updates {
+		bot.Push(update)
+	}
+	bot.Close()
+	<-done
+
+	return appState
+}
+
+func textUpdate(chatId domain.ChatID, text string) messenger.Update {
+	return messenger.Update{Message: &messenger.Message{
+		ChatID:         chatId,
+		SenderID:       testUser,
+		SenderUsername: "user",
+		SenderLanguage: "en-US",
+		Text:           text,
+		IsGroup:        chatId < 0,
+	}}
+}
+
+// lastText returns the text of the last message sent to the chat.
+func lastText(bot *messenger.Recorder, chatId domain.ChatID) string {
+	messages := bot.Messages(chatId)
+	if len(messages) == 0 {
+		return ""
+	}
+	return messages[len(messages)-1].Text
+}
+
+func TestCommandMenuLoop(t *testing.T) {
+	bot := messenger.NewRecorder()
+	bot.SetUsername(testUser, "user")
+	english := i18n.English()
+
+	runCommandMenu(t, bot,
+		textUpdate(testUser, "/help"),
+		textUpdate(testUser, "hello"),
+		textUpdate(testUser, "/join"),
+		textUpdate(testGroup, "/join@go4pom_bot"),
+		textUpdate(testUser, "/language it"),
+		textUpdate(testUser, "/p"),
+	)
+
+	messages := bot.Messages(testUser)
+	var texts []string
+	for _, message := range messages {
+		texts = append(texts, message.Text)
+	}
+
+	// A new chat gets the info and the help, then the help again.
+	if len(texts) < 4 || !strings.HasPrefix(texts[3], english.T("help")) {
+		t.Fatalf("/help not answered: %q", texts)
+	}
+	if !messages[1].HTML {
+		t.Errorf("the license notice should be sent in HTML")
+	}
+	// "hello" is not for the bot.
+	if texts[4] != english.T("only_groups") {
+		t.Errorf("/join in a private chat: %q", texts[4])
+	}
+	if got := lastText(bot, testGroup); got != english.T("subscribed", "user") {
+		t.Errorf("/join in a group: %q", got)
+	}
+	italian := i18n.NewBundle().Localizer("it")
+	if texts[5] != italian.T("language_set") || texts[6] != italian.T("session_not_running") {
+		t.Errorf("the bot should speak Italian after /language it: %q", texts[5:])
+	}
+	if len(texts) != 7 {
+		t.Errorf("unexpected messages: %q", texts)
+	}
+
+	// Private chats, groups and the only admin, in each language.
+	if got, expected := len(bot.Commands()), 3*len(i18n.NewBundle().Languages()); got != expected {
+		t.Errorf("%d command lists published, expected %d", got, expected)
+	}
+}
+
+func TestCommandMenuLoopCallbacks(t *testing.T) {
+	bot := messenger.NewRecorder()
+	english := i18n.English()
+
+	runCommandMenu(t, bot, textUpdate(testUser, "/clessidra"))
+	messages := bot.Messages(testUser)
+	hourglass := messages[len(messages)-1]
+	if hourglass.Text != english.T("hourglass") || len(hourglass.Keyboard) == 0 {
+		t.Fatalf("the hourglass should have the session buttons: %+v", hourglass)
+	}
+
+	bot = messenger.NewRecorder()
+	runCommandMenu(t, bot,
+		textUpdate(testUser, "/clessidra"),
+		messenger.Update{Callback: &messenger.Callback{
+			ID:        "query",
+			ChatID:    testUser,
+			SenderID:  testUser,
+			MessageID: len(messages),
+			Data:      callbackStatus,
+			Keyboard:  hourglass.Keyboard,
+		}},
+		messenger.Update{Callback: &messenger.Callback{ID: "old", SenderID: testUser, Data: callbackStatus}},
+	)
+
+	answers := bot.Answers()
+	if len(answers) != 2 || answers[0].CallbackID != "query" || answers[0].Text == "" {
+		t.Fatalf("the button press should be answered with the time left: %+v", answers)
+	}
+	if answers[1].CallbackID != "old" || answers[1].Text != "" {
+		t.Errorf("presses on unknown messages should be answered with no text: %+v", answers[1])
+	}
+	if edits := bot.Messages(testUser)[len(messages)-1].Edits; edits != 0 {
+		t.Errorf("the buttons did not change, so they should not be edited (%d edits)", edits)
+	}
+}
+
+func TestFlowSurvivesDefaultSession(t *testing.T) {
+	bot := messenger.NewRecorder()
+
+	appState := runCommandMenu(t, bot,
+		textUpdate(testUser, "/flow on"),
+		textUpdate(testUser, "/d"),
+	)
+
+	session := appState.ReadSettings(testUser).SessionRunning
+	if session == nil || !session.IsFlow() {
+		t.Fatalf("the session started with /d should be in flow mode")
+	}
+
+	bot = messenger.NewRecorder()
+	appState = runCommandMenu(t, bot,
+		textUpdate(testUser, "/flow on"),
+		textUpdate(testUser, "/flow off"),
+		textUpdate(testUser, "/25for2"),
+	)
+
+	session = appState.ReadSettings(testUser).SessionRunning
+	if session == nil || session.IsFlow() {
+		t.Errorf("the session should not be in flow mode after /flow off")
+	}
+}
+
+func TestCommandMenuLoopIgnoresOtherBots(t *testing.T) {
+	bot := messenger.NewRecorder()
+	english := i18n.English()
+
+	runCommandMenu(t, bot,
+		textUpdate(testGroup, "/help@go4pom_bot"),
+		textUpdate(testGroup, "/5@otherbot"),
+		textUpdate(testGroup, "/25forever@otherbot"),
+		textUpdate(testGroup, "/help@otherbot"),
+	)
+	if got := lastText(bot, testGroup); !strings.HasPrefix(got, english.T("help")) {
+		t.Errorf("the commands for other bots should not be answered: %q", got)
+	}
+
+	bot = messenger.NewRecorder()
+	runCommandMenu(t, bot, textUpdate(testGroup, "/25forever@go4pom_bot"))
+	if got := lastText(bot, testGroup); !strings.Contains(got, "\"v\"") {
+		t.Errorf("the invalid patterns for this bot should be answered: %q", got)
+	}
+}
@@ -0,0 +1,601 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/inputprocess"
+	"GoforPomodoro/internal/messenger"
+	"GoforPomodoro/internal/sessionmanager"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commandScope tells in which chats, and to whom, a command is available.
+type commandScope int
+
+const (
+	scopeAll     commandScope = iota // private chats and groups
+	scopePrivate                     // private chats only
+	scopeGroup                       // groups only
+	scopeAdmin                       // the admins of the bot only
+)
+
+// command is a command of the bot, e.g. /pause.
+type command struct {
+	// Name is the command without the slash, e.g. "pause".
+	Name string
+
+	// Aliases are other names of the command, e.g. "p" for /p.
+	Aliases []string
+
+	// Params shows the parameters of the command in /help, e.g. "on|off".
+	Params string
+
+	// Description is the key of the message describing the command in /help
+	// and in the command list of Telegram. Commands with no description are
+	// not shown.
+	Description string
+
+	Scope   commandScope
+	Handler commandHandler
+}
+
+// commandContext is what a commandHandler needs to act on a command.
+type commandContext struct {
+	settings     *domain.AppSettings
+	appState     *domain.AppState
+	bot          messenger.Messenger
+	chatId       domain.ChatID
+	senderId     domain.ChatID
+	isGroup      bool
+	isAdmin      bool
+	parameters   []string
+	communicator *Communicator
+	registry     *commandRegistry
+
+	// stop is set by the handlers after which no more updates must be
+	// processed.
+	stop bool
+}
+
+// commandHandler Act on a command.
+type commandHandler func(ctx *commandContext)
+
+// commandRegistry holds the commands of the bot, in the order they are shown
+// in /help.
+type commandRegistry struct {
+	commands []*command
+	byName   map[string]*command
+}
+
+// newCommandRegistry returns a registry of the commands. It panics if two
+// commands share a name or an alias.
+func newCommandRegistry(commands ...command) *commandRegistry {
+	registry := &commandRegistry{byName: make(map[string]*command)}
+	for i := range commands {
+		cmd := &commands[i]
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if _, ok := registry.byName[name]; ok {
+				panic(fmt.Sprintf("command /%s registered twice", name))
+			}
+			registry.byName[name] = cmd
+		}
+		registry.commands = append(registry.commands, cmd)
+	}
+	return registry
+}
+
+// lookup returns the command with the given name or alias (e.g. "/p").
+func (r *commandRegistry) lookup(text string) (*command, bool) {
+	if !strings.HasPrefix(text, "/") {
+		return nil, false
+	}
+	cmd, ok := r.byName[strings.TrimPrefix(text, "/")]
+	return cmd, ok
+}
+
+// available returns the described commands that can be used in a chat.
+func (r *commandRegistry) available(isGroup bool, isAdmin bool) []*command {
+	var commands []*command
+	for _, cmd := range r.commands {
+		if cmd.Description == "" {
+			continue
+		}
+		switch {
+		case cmd.Scope == scopePrivate && isGroup,
+			cmd.Scope == scopeGroup && !isGroup,
+			cmd.Scope == scopeAdmin && !isAdmin:
+			continue
+		}
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+// dispatch Check the scope of the command and run its handler.
+func (r *commandRegistry) dispatch(cmd *command, ctx *commandContext) {
+	switch {
+	case cmd.Scope == scopeAdmin && !ctx.isAdmin:
+		// Admin commands are unknown to the others.
+		return
+	case cmd.Scope == scopeGroup && !ctx.isGroup:
+		ctx.communicator.OnlyGroupsCommand()
+		return
+	case cmd.Scope == scopePrivate && ctx.isGroup:
+		ctx.communicator.OnlyPrivateCommand()
+		return
+	}
+	cmd.Handler(ctx)
+}
+
+// helpText Print the help of the commands that can be used in a chat.
+func (r *commandRegistry) helpText(l *i18n.Localizer, isGroup bool, isAdmin bool) string {
+	var builder strings.Builder
+	builder.WriteString(l.T("help"))
+	builder.WriteString("\n\n" + l.T("help_commands"))
+	for _, cmd := range r.available(isGroup, isAdmin) {
+		builder.WriteString("\n")
+		for _, alias := range cmd.Aliases {
+			builder.WriteString("(/" + alias + ") ")
+		}
+		builder.WriteString("/" + cmd.Name)
+		if cmd.Params != "" {
+			builder.WriteString(" " + cmd.Params)
+		}
+		builder.WriteString(" — " + l.T(cmd.Description))
+	}
+	return builder.String()
+}
+
+// commandList returns the command list of the apps for a chat.
+func (r *commandRegistry) commandList(l *i18n.Localizer, isGroup bool, isAdmin bool) []messenger.CommandInfo {
+	var commands []messenger.CommandInfo
+	for _, cmd := range r.available(isGroup, isAdmin) {
+		commands = append(commands, messenger.CommandInfo{Name: cmd.Name, Description: l.T(cmd.Description)})
+	}
+	return commands
+}
+
+// publish Send the command lists to the messenger (setMyCommands, on
+// Telegram), so that the apps of the users can suggest the commands: one for
+// private chats, one for groups and one for the private chats of the admins,
+// in each language of the bot.
+func (r *commandRegistry) publish(bot messenger.Messenger, messages *i18n.Bundle, adminIds []domain.ChatID) {
+	for _, catalog := range messages.Languages() {
+		l := messages.Localizer(catalog.Code)
+
+		// The commands in DefaultLanguage are also the ones of the users
+		// whose language the bot does not speak.
+		languageCode := catalog.Code
+		if languageCode == i18n.DefaultLanguage {
+			languageCode = ""
+		}
+
+		lists := []messenger.CommandList{
+			{Chats: messenger.PrivateChats, Language: languageCode, Commands: r.commandList(l, false, false)},
+			{Chats: messenger.GroupChats, Language: languageCode, Commands: r.commandList(l, true, false)},
+		}
+		for _, adminId := range adminIds {
+			lists = append(lists, messenger.CommandList{
+				Chats: messenger.SingleChat, ChatID: adminId, Language: languageCode, Commands: r.commandList(l, false, true),
+			})
+		}
+
+		for _, list := range lists {
+			if err := bot.SetCommands(list); err != nil {
+				log.Printf("[commandRegistry::publish] error (chats %d, %s): %v\n",
+					list.Chats, catalog.Code, err.Error())
+			}
+		}
+	}
+}
+
+// botCommandRegistry returns the registry of all the commands of the bot.
+//
+// Session patterns (e.g. /25for4) are not commands: see sessionPatternCommand.
+func botCommandRegistry() *commandRegistry {
+	return newCommandRegistry(
+		command{Name: "start_sprint", Aliases: []string{"s"}, Description: "help_start_sprint", Handler: startSprintCommand},
+		command{Name: "pause", Aliases: []string{"p"}, Description: "help_pause", Handler: pauseCommand},
+		command{Name: "resume", Description: "help_resume", Handler: resumeCommand},
+		command{Name: "cancel", Aliases: []string{"c"}, Description: "help_cancel", Handler: cancelCommand},
+		command{Name: "skip", Description: "help_skip", Handler: skipCommand},
+		command{Name: "extend", Params: "[5]", Description: "help_extend", Handler: extendCommand},
+		command{Name: "next", Description: "help_next", Handler: nextCommand},
+		command{Name: "break", Description: "help_break", Handler: breakCommand},
+		command{Name: "restart", Description: "help_restart", Handler: restartCommand},
+		command{Name: "interrupt", Params: "internal|external ...", Description: "help_interrupt", Handler: interruptCommand},
+		command{Name: "session", Aliases: []string{"se"}, Description: "help_session", Handler: sessionCommand},
+		command{Name: "default", Aliases: []string{"d"}, Description: "help_default", Handler: defaultCommand},
+		command{Name: "plan", Params: "50/10 25/5 ...", Description: "help_plan", Handler: planCommand},
+		command{Name: "preset", Params: "[save|list|delete|default] ...", Description: "help_preset", Handler: presetCommand},
+		command{Name: "at", Params: "09:00 25for4", Description: "help_at", Handler: atCommand},
+		command{Name: "every", Params: "weekday 09:00 25for4", Description: "help_every", Handler: everyCommand},
+		command{Name: "schedules", Description: "help_schedules", Handler: schedulesCommand},
+		command{Name: "unschedule", Params: "3|all", Description: "help_unschedule", Handler: unscheduleCommand},
+		command{Name: "timezone", Params: "[Europe/Rome]", Description: "help_timezone", Handler: timezoneCommand},
+		command{Name: "stats", Description: "help_stats", Handler: statsCommand},
+		command{Name: "goal", Params: "[8|off]", Description: "help_goal", Handler: goalCommand},
+		command{Name: "warn", Params: "[2|off]", Description: "help_warn", Handler: warnCommand},
+		command{Name: "live", Params: "[on|off]", Description: "help_live", Handler: liveCommand},
+		command{Name: "flow", Params: "[on|off]", Description: "help_flow", Handler: flowCommand},
+		command{Name: "manual", Params: "[on [10]|off]", Description: "help_manual", Handler: manualCommand},
+		command{Name: "autorun", Params: "[on|off]", Description: "help_autorun", Handler: autorunCommand},
+		command{Name: "limits", Params: "[set|unset|reset] ...", Description: "help_limits", Handler: limitsCommand},
+		command{Name: "language", Params: "[it|auto]", Description: "help_language", Handler: languageCommand},
+		command{Name: "join", Scope: scopeGroup, Description: "help_join", Handler: joinCommand},
+		command{Name: "leave", Scope: scopeGroup, Description: "help_leave", Handler: leaveCommand},
+		command{Name: "reset", Description: "help_reset", Handler: resetCommand},
+		command{Name: "help", Description: "help_help", Handler: helpCommand},
+		command{Name: "info", Description: "help_info", Handler: infoCommand},
+		command{Name: "shutdown", Scope: scopeAdmin, Description: "help_shutdown", Handler: shutdownCommand},
+		command{Name: "clessidra", Handler: hourglassCommand},
+	)
+}
+
+func startSprintCommand(ctx *commandContext) {
+	ActionStartSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator)
+}
+
+func pauseCommand(ctx *commandContext) {
+	ActionPauseSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator)
+}
+
+func resumeCommand(ctx *commandContext) {
+	ActionResumeSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator)
+}
+
+func cancelCommand(ctx *commandContext) {
+	ActionCancelSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator)
+}
+
+func skipCommand(ctx *commandContext) {
+	ActionSkipPhase(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator)
+}
+
+func extendCommand(ctx *commandContext) {
+	maxExtension := ctx.settings.MaxPhaseExtension()
+	minutes := 5
+	if len(ctx.parameters) > 0 {
+		var err error
+		minutes, err = strconv.Atoi(ctx.parameters[0])
+		if err != nil || minutes <= 0 || minutes > int(maxExtension/time.Minute) {
+			ctx.communicator.CommandError()
+			return
+		}
+	}
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	err := sessionmanager.ExtendPhase(
+		session,
+		time.Duration(minutes)*time.Minute,
+		maxExtension,
+	)
+	ctx.communicator.PhaseExtended(err)
+}
+
+func nextCommand(ctx *commandContext) {
+	ActionNextSprint(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator)
+}
+
+func breakCommand(ctx *commandContext) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	err := sessionmanager.TakeBreak(session)
+	ctx.communicator.BreakTaken(err)
+}
+
+func restartCommand(ctx *commandContext) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	err := sessionmanager.RestartSprint(session)
+	ctx.communicator.SprintRestarted(err)
+}
+
+func interruptCommand(ctx *commandContext) {
+	if len(ctx.parameters) == 0 || ctx.parameters[0] == "" {
+		ctx.communicator.InterruptUsage()
+		return
+	}
+	kind, ok := domain.ParseInterruptionKind(ctx.parameters[0])
+	if !ok {
+		ctx.communicator.InterruptUsage()
+		return
+	}
+	note := []rune(strings.TrimSpace(strings.Join(ctx.parameters[1:], " ")))
+	if len(note) > domain.MaxInterruptionNoteLength {
+		note = note[:domain.MaxInterruptionNoteLength]
+	}
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	if session.State() != domain.StatePausedSprint {
+		err := sessionmanager.InterruptSession(session, kind, string(note))
+		ctx.communicator.Interrupted(err)
+		return
+	}
+	interruption, err := sessionmanager.InterruptPausedSession(ctx.appState, ctx.chatId, session, kind, string(note))
+	if err == nil {
+		data.UpdateUserSessionRunning(ctx.appState, ctx.chatId)
+	}
+	ctx.communicator.InterruptionLoggedHandler(ctx.chatId, session, interruption, err)
+}
+
+func sessionCommand(ctx *commandContext) {
+	session := data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId)
+	ctx.communicator.SessionState(*session)
+}
+
+func defaultCommand(ctx *commandContext) {
+	ActionStartDefaultSession(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator)
+}
+
+func planCommand(ctx *commandContext) {
+	sessionData, err := inputprocess.ParsePlanToSession(ctx.parameters)
+	if err != nil {
+		ctx.communicator.PlanError(err)
+		return
+	}
+	ActionSetSession(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator, sessionData)
+}
+
+func presetCommand(ctx *commandContext) {
+	ActionPreset(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator, ctx.parameters)
+}
+
+func atCommand(ctx *commandContext) {
+	ActionSchedule(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator, false, ctx.parameters)
+}
+
+func everyCommand(ctx *commandContext) {
+	ActionSchedule(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator, true, ctx.parameters)
+}
+
+func schedulesCommand(ctx *commandContext) {
+	schedules, err := data.GetSchedules(ctx.appState, ctx.chatId)
+	now := ctx.appState.GetClock().Now().In(data.GetLocation(ctx.appState, ctx.chatId))
+	ctx.communicator.ScheduleList(err, schedules, now)
+}
+
+func unscheduleCommand(ctx *commandContext) {
+	ActionUnschedule(ctx.chatId, ctx.appState, ctx.communicator, ctx.parameters)
+}
+
+func timezoneCommand(ctx *commandContext) {
+	if len(ctx.parameters) == 0 || ctx.parameters[0] == "" {
+		ctx.communicator.Timezone(data.GetLocation(ctx.appState, ctx.chatId), ctx.appState.GetClock().Now())
+		return
+	}
+	err := data.SetTimezone(ctx.appState, ctx.chatId, ctx.parameters[0])
+	ctx.communicator.TimezoneSet(err, data.GetLocation(ctx.appState, ctx.chatId), ctx.appState.GetClock().Now())
+}
+
+func statsCommand(ctx *commandContext) {
+	today, week, allTime, err := data.GetFocusStats(ctx.appState, ctx.chatId)
+	ctx.communicator.Stats(err, today, week, allTime)
+}
+
+func goalCommand(ctx *commandContext) {
+	if len(ctx.parameters) == 0 || ctx.parameters[0] == "" {
+		progress, err := data.GetGoalProgress(ctx.appState, ctx.chatId)
+		ctx.communicator.Goal(err, progress)
+		return
+	}
+	goal := 0
+	if strings.ToLower(ctx.parameters[0]) != "off" {
+		var err error
+		goal, err = strconv.Atoi(ctx.parameters[0])
+		if err != nil || goal < 0 || goal > domain.MaxDailyGoal {
+			ctx.communicator.CommandError()
+			return
+		}
+	}
+	data.SetDailyGoal(ctx.appState, ctx.chatId, goal)
+	ctx.communicator.GoalSet(goal)
+}
+
+func warnCommand(ctx *commandContext) {
+	if len(ctx.parameters) == 0 || ctx.parameters[0] == "" {
+		ctx.communicator.WarnLead(int(data.GetWarnLead(ctx.appState, ctx.chatId).Minutes()))
+		return
+	}
+	minutes := 0
+	if strings.ToLower(ctx.parameters[0]) != "off" {
+		var err error
+		minutes, err = strconv.Atoi(ctx.parameters[0])
+		if err != nil || minutes < 0 || minutes > domain.MaxWarnLeadMinutes {
+			ctx.communicator.CommandError()
+			return
+		}
+	}
+	data.SetWarnLead(ctx.appState, ctx.chatId, minutes)
+	ctx.communicator.WarnLeadSet(minutes)
+}
+
+func liveCommand(ctx *commandContext) {
+	if len(ctx.parameters) == 0 || ctx.parameters[0] == "" {
+		ctx.communicator.LiveStatus(data.IsLiveStatusEnabled(ctx.appState, ctx.chatId))
+		return
+	}
+	switch strings.ToLower(ctx.parameters[0]) {
+	case "on":
+		data.SetLiveStatus(ctx.appState, ctx.chatId, true)
+		ctx.communicator.LiveStatusSet(true, data.GetUserSessionRunning(ctx.appState, ctx.chatId, ctx.senderId))
+	case "off":
+		data.SetLiveStatus(ctx.appState, ctx.chatId, false)
+		ctx.communicator.LiveStatusSet(false, nil)
+	default:
+		ctx.communicator.CommandError()
+	}
+}
+
+func flowCommand(ctx *commandContext) {
+	if len(ctx.parameters) == 0 || ctx.parameters[0] == "" {
+		ctx.communicator.Flow(data.IsFlowEnabled(ctx.appState, ctx.chatId))
+		return
+	}
+	switch strings.ToLower(ctx.parameters[0]) {
+	case "on":
+		data.SetFlow(ctx.appState, ctx.chatId, true)
+		ctx.communicator.FlowSet(true)
+	case "off":
+		data.SetFlow(ctx.appState, ctx.chatId, false)
+		ctx.communicator.FlowSet(false)
+	default:
+		ctx.communicator.CommandError()
+	}
+}
+
+func manualCommand(ctx *commandContext) {
+	if len(ctx.parameters) == 0 || ctx.parameters[0] == "" {
+		ctx.communicator.ManualAdvance(data.GetManualAdvance(ctx.appState, ctx.chatId))
+		return
+	}
+	switch strings.ToLower(ctx.parameters[0]) {
+	case "on":
+		timeoutMinutes := 0
+		if len(ctx.parameters) > 1 {
+			var err error
+			timeoutMinutes, err = strconv.Atoi(ctx.parameters[1])
+			if err != nil || timeoutMinutes < 0 || timeoutMinutes > domain.MaxReadyTimeoutMinutes {
+				ctx.communicator.CommandError()
+				return
+			}
+		}
+		data.SetManualAdvance(ctx.appState, ctx.chatId, true, timeoutMinutes)
+		ctx.communicator.ManualAdvanceSet(true, timeoutMinutes)
+	case "off":
+		data.SetManualAdvance(ctx.appState, ctx.chatId, false, 0)
+		ctx.communicator.ManualAdvanceSet(false, 0)
+	default:
+		ctx.communicator.CommandError()
+	}
+}
+
+func autorunCommand(ctx *commandContext) {
+	autorun := true
+	if len(ctx.parameters) > 0 {
+		switch ctx.parameters[0] {
+		case "on":
+			autorun = true
+		case "off":
+			autorun = false
+		default:
+			ctx.communicator.CommandError()
+			return
+		}
+	}
+	data.SetUserAutorun(ctx.appState, ctx.chatId, ctx.senderId, autorun)
+	if autorun {
+		ctx.communicator.ReplyWith(ctx.communicator.Localizer().T("autorun_on"))
+	} else {
+		ctx.communicator.ReplyWith(ctx.communicator.Localizer().T("autorun_off"))
+	}
+}
+
+func limitsCommand(ctx *commandContext) {
+	ActionLimits(ctx.chatId, ctx.appState, ctx.communicator, ctx.parameters, ctx.isAdmin)
+}
+
+func languageCommand(ctx *commandContext) {
+	if len(ctx.parameters) == 0 || ctx.parameters[0] == "" {
+		ctx.communicator.Language(data.GetLanguage(ctx.appState, ctx.chatId) == "")
+		return
+	}
+	if ctx.parameters[0] == "auto" {
+		data.SetLanguage(ctx.appState, ctx.chatId, "")
+		ctx.communicator.LanguageSet(true)
+		return
+	}
+	language, ok := ctx.appState.Messages.Match(ctx.parameters[0])
+	if !ok {
+		ctx.communicator.LanguageUnknown(ctx.parameters[0])
+		return
+	}
+	data.SetLanguage(ctx.appState, ctx.chatId, language)
+	ctx.communicator.LanguageSet(false)
+}
+
+func joinCommand(ctx *commandContext) {
+	username, err := ctx.bot.Username(ctx.senderId)
+	if err != nil {
+		ctx.communicator.ReplyWith(ctx.communicator.Localizer().T("account_error"))
+		return
+	}
+
+	ctx.communicator.Subscribe(
+		data.SubscribeUserInGroup(ctx.appState, ctx.chatId, ctx.senderId),
+		username,
+	)
+}
+
+func leaveCommand(ctx *commandContext) {
+	ctx.communicator.Unsubscribe(data.UnsubscribeUser(ctx.appState, ctx.chatId, ctx.senderId))
+}
+
+func resetCommand(ctx *commandContext) {
+	if err := ActionUnscheduleAll(ctx.chatId, ctx.appState); err != nil {
+		log.Printf("[resetCommand] error in unscheduling: %v\n", err.Error())
+	}
+	data.CleanUserSettings(ctx.appState, ctx.chatId, ctx.senderId)
+	ctx.communicator.DataCleaned()
+}
+
+func helpCommand(ctx *commandContext) {
+	ctx.communicator.Help(ctx.registry, ctx.isAdmin)
+}
+
+func infoCommand(ctx *commandContext) {
+	ctx.communicator.Info()
+}
+
+func shutdownCommand(ctx *commandContext) {
+	ctx.communicator.ReplyWith(ctx.communicator.Localizer().T("shutting_down"))
+	data.PrepareForShutdown(
+		ctx.appState,
+		func() {
+			ctx.communicator.ReplyWith(ctx.communicator.Localizer().T("db_lock_acquired"))
+			os.Exit(0)
+		},
+	)
+	ctx.stop = true
+}
+
+func hourglassCommand(ctx *commandContext) {
+	ctx.communicator.Hourglass()
+}
+
+// sessionPatternCommand Set the session of a session pattern, e.g. /25for4.
+// Texts that are not session patterns are not for the bot, and are ignored.
+func sessionPatternCommand(ctx *commandContext, text string) {
+	if !inputprocess.IsSessionPattern(text) {
+		return
+	}
+	if strings.Contains(text, "@") {
+		// The name of this bot was stripped (see inputprocess.CommandFrom):
+		// the command is for another bot of the group.
+		return
+	}
+	pattern := strings.Join(append([]string{text}, ctx.parameters...), " ")
+	sessionData, err := inputprocess.ParseSession(pattern)
+	if err != nil {
+		ctx.communicator.SessionPatternError(err)
+		return
+	}
+	ActionSetSession(ctx.senderId, ctx.chatId, ctx.appState, ctx.communicator, sessionData)
+}
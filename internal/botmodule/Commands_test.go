@@ -0,0 +1,115 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/i18n"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCommandRegistry(t *testing.T) {
+	registry := botCommandRegistry()
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"/pause", "pause"},
+		{"/p", "pause"},
+		{"/se", "session"},
+		{"/clessidra", "clessidra"},
+		{"pause", ""},
+		{"/25for4", ""},
+		{"/unknown", ""},
+	}
+	for _, test := range tests {
+		cmd, ok := registry.lookup(test.text)
+		if ok != (test.expected != "") || (ok && cmd.Name != test.expected) {
+			t.Errorf("lookup(%q) should find %q", test.text, test.expected)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering an alias twice should panic")
+		}
+	}()
+	newCommandRegistry(command{Name: "pause", Aliases: []string{"p"}}, command{Name: "p"})
+}
+
+func TestCommandDescriptions(t *testing.T) {
+	validName := regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+	bundle := i18n.NewBundle()
+
+	for _, cmd := range botCommandRegistry().commands {
+		if !validName.MatchString(cmd.Name) {
+			t.Errorf("/%s is not a valid name for Telegram", cmd.Name)
+		}
+		if cmd.Description == "" {
+			continue
+		}
+		for _, catalog := range bundle.Languages() {
+			description := bundle.Localizer(catalog.Code).T(cmd.Description)
+			if description == cmd.Description {
+				t.Errorf("/%s: no description in %q", cmd.Name, catalog.Code)
+			}
+			if len(description) < 3 || len(description) > 256 {
+				t.Errorf("/%s: the description in %q is not 3-256 characters long", cmd.Name, catalog.Code)
+			}
+		}
+	}
+}
+
+func TestCommandScopes(t *testing.T) {
+	registry := botCommandRegistry()
+	names := func(isGroup bool, isAdmin bool) string {
+		var names []string
+		for _, cmd := range registry.available(isGroup, isAdmin) {
+			names = append(names, "/"+cmd.Name+" ")
+		}
+		return strings.Join(names, "")
+	}
+
+	tests := []struct {
+		isGroup, isAdmin bool
+		name             string
+		expected         bool
+	}{
+		{false, false, "/pause ", true},
+		{true, false, "/pause ", true},
+		{false, false, "/join ", false},
+		{true, false, "/join ", true},
+		{false, false, "/shutdown ", false},
+		{false, true, "/shutdown ", true},
+		{false, true, "/clessidra ", false},
+	}
+	for _, test := range tests {
+		if got := strings.Contains(names(test.isGroup, test.isAdmin), test.name); got != test.expected {
+			t.Errorf("%s available (group: %v, admin: %v) = %v, expected %v",
+				test.name, test.isGroup, test.isAdmin, got, test.expected)
+		}
+	}
+
+	help := registry.helpText(i18n.English(), false, false)
+	if !strings.Contains(help, "\n(/p) /pause — Pause the running session") {
+		t.Errorf("/pause missing from help:\n%s", help)
+	}
+	if strings.Contains(help, "/shutdown") || strings.Contains(help, "/join") {
+		t.Errorf("help should show only the commands of the chat:\n%s", help)
+	}
+}
@@ -18,32 +18,25 @@ package botmodule
 import (
 	"GoforPomodoro/internal/data"
 	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/messenger"
 	"GoforPomodoro/internal/sessionmanager"
-	"GoforPomodoro/internal/utils"
 	"fmt"
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"log"
-	"math/rand"
 	"strings"
 	"time"
 )
 
-var simpleHourglassKeyboard = tgbotapi.NewInlineKeyboardMarkup(
-	tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("⌛", "⌛"),
-	),
-)
-
 type Communicator struct {
 	appState     *domain.AppState
 	appVariables *domain.AppVariables
 	ChatID       domain.ChatID
-	Bot          *tgbotapi.BotAPI
+	Bot          messenger.Messenger
 	Subscribers  []domain.ChatID
 	IsGroup      bool
 }
 
-func GetCommunicator(appState *domain.AppState, appVariables *domain.AppVariables, chatId domain.ChatID, bot *tgbotapi.BotAPI) *Communicator {
+func GetCommunicator(appState *domain.AppState, appVariables *domain.AppVariables, chatId domain.ChatID, bot messenger.Messenger) *Communicator {
 	communicator := new(Communicator)
 
 	communicator.appState = appState
@@ -56,20 +49,23 @@ func GetCommunicator(appState *domain.AppState, appVariables *domain.AppVariable
 	return communicator
 }
 
-func (c *Communicator) subscribersAsString() string {
-	bot := c.Bot
+// Localizer returns the localizer of the language the bot speaks in the chat.
+func (c *Communicator) Localizer() *i18n.Localizer {
+	return data.GetLocalizer(c.appState, c.ChatID)
+}
 
+func (c *Communicator) subscribersAsString() string {
 	var sb strings.Builder
 
 	errors := 0
 	for _, id := range c.Subscribers {
-		subscriberChat, err := bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: int64(id)}})
+		username, err := c.Bot.Username(id)
 		if err != nil {
 			errors += 1
 			continue
 		}
 		sb.WriteString("@")
-		sb.WriteString(subscriberChat.UserName)
+		sb.WriteString(username)
 		sb.WriteString(" ")
 	}
 
@@ -88,17 +84,16 @@ func (c *Communicator) toNotify(message string) string {
 	return message + "\n\n———\n" + c.subscribersAsString()
 }
 
-func (c *Communicator) Subscribe(err error, update tgbotapi.Update, username string) {
+func (c *Communicator) Subscribe(err error, username string) {
 	if err != nil {
 		switch err.Error() {
 		case domain.AlreadySubscribed{}.Error():
-			c.ReplyWith("You already subscribed this chat group.\n\n" +
-				"Remember you can use /leave to cancel subscription.")
+			c.ReplyWith(c.Localizer().T("already_subscribed"))
 		case domain.SubscriptionError{}.Error():
-			c.ReplyWith("There has been an error with this operation (subscription).")
+			c.ReplyWith(c.Localizer().T("subscription_error"))
 		}
 	} else {
-		c.ReplyWith(fmt.Sprintf("Done! You will be tagged (@%s) in sprints' messages.", username))
+		c.ReplyWith(c.Localizer().T("subscribed", username))
 	}
 }
 
@@ -106,34 +101,26 @@ func (c *Communicator) Unsubscribe(err error) {
 	if err != nil {
 		switch err.Error() {
 		case domain.AlreadyUnsubscribed{}.Error():
-			c.ReplyWith("You are (were) not subscribed in this chat group.")
+			c.ReplyWith(c.Localizer().T("not_subscribed"))
 		case domain.SubscriptionError{}.Error():
-			c.ReplyWith("There has been an error with this operation (subscription).")
+			c.ReplyWith(c.Localizer().T("subscription_error"))
 		}
 	} else {
-		c.ReplyWith("Done! You no longer subscribe in this chat group thus will not be tagged in future messages.")
+		c.ReplyWith(c.Localizer().T("unsubscribed"))
 	}
 }
 
 func (c *Communicator) ReplyWith(text string) {
-	bot := c.Bot
-	chatId := int64(c.ChatID)
-
-	msg := tgbotapi.NewMessage(chatId, text)
-	_, err := bot.Send(msg)
+	_, err := c.Bot.SendText(c.ChatID, text)
 	if err != nil {
 		log.Printf("ERROR: %s", err.Error())
 	}
 }
 
-func (c *Communicator) ReplyWithParseMode(text string, parseMode string, disablePreview bool) {
-	bot := c.Bot
-	chatId := int64(c.ChatID)
-
-	msg := tgbotapi.NewMessage(chatId, text)
-	msg.ParseMode = parseMode
-	msg.DisableWebPagePreview = disablePreview
-	_, err := bot.Send(msg)
+// ReplyWithHTML Reply with a message formatted in HTML, with no link
+// previews.
+func (c *Communicator) ReplyWithHTML(text string) {
+	_, err := c.Bot.SendHTML(c.ChatID, text)
 	if err != nil {
 		log.Printf("ERROR: %s", err.Error())
 	}
@@ -143,32 +130,38 @@ func (c *Communicator) ReplyAndNotify(text string) {
 	c.ReplyWith(c.toNotify(text))
 }
 
-func (c *Communicator) ReplyWithAndHourglass(text string) {
-	msg := tgbotapi.NewMessage(int64(c.ChatID), text)
-	msg.ReplyMarkup = simpleHourglassKeyboard
-	_, err := c.Bot.Send(msg)
+// ReplyWithAndControls Reply with the buttons that control a session in the
+// given state.
+func (c *Communicator) ReplyWithAndControls(text string, state domain.SessionState) {
+	_, err := c.Bot.SendWithKeyboard(c.ChatID, text, sessionKeyboard(c.Localizer(), state))
 	if err != nil {
 		log.Printf("ERROR: %s", err.Error())
 	}
 }
 
-func (c *Communicator) ReplyWithAndHourglassAndNotify(text string) {
-	c.ReplyWithAndHourglass(c.toNotify(text))
+func (c *Communicator) ReplyWithAndControlsAndNotify(text string, state domain.SessionState) {
+	c.ReplyWithAndControls(c.toNotify(text), state)
 }
 
 func (c *Communicator) SessionStarted(session *domain.Session, err error) {
+	l := c.Localizer()
 	if err == nil {
 		sessionTime := session.CalculateSessionTimeInSeconds()
 		var replyStr string
 
 		if session.IsSprintDurationUnspecified() {
-			replyStr = "This session will go as long as you want to keep focusing."
+			replyStr = l.T("session_started_unspecified")
 		} else {
-			replyStr = fmt.Sprintf("This session will last for %s\n\nSession started!", utils.NiceTimeFormatting64(sessionTime))
+			replyStr = l.T("session_started_lasts", l.Duration(sessionTime))
+			if endTime, ok := session.SessionEndTime(); ok {
+				replyStr += l.T("session_started_ends_at", endTime.In(session.Location()).Format("15:04"))
+			}
+			replyStr += l.T("session_started")
 		}
-		c.ReplyWithAndHourglassAndNotify(replyStr)
+		c.ReplyWithAndControlsAndNotify(replyStr, session.State())
+		c.StartLiveStatus(session)
 	} else {
-		c.ReplyWith("Session was not set.\nPlease set a session or use /default for classic 4x25m+25m.")
+		c.ReplyWith(l.T("session_not_set_default"))
 	}
 }
 
@@ -185,149 +178,708 @@ func (c *Communicator) SessionPaused() {
 
 }*/
 
+// SessionHandlers returns the handlers to notify the chat of the events of
+// its session.
+func (c *Communicator) SessionHandlers() sessionmanager.SessionHandlers {
+	return sessionmanager.SessionHandlers{
+		RestBegin:     c.RestBeginHandler,
+		RestFinished:  c.RestFinishedHandler,
+		SessionEnd:    c.SessionFinishedHandler,
+		SessionPaused: c.SessionPausedHandler,
+		PhaseExtended: c.PhaseExtendedHandler,
+
+		InterruptionLogged: c.InterruptionLoggedHandler,
+		SprintRestarted:    c.SprintRestartedHandler,
+		PhaseEnding:        c.PhaseEndingHandler,
+		OvertimeBegin:      c.OvertimeBeginHandler,
+		SessionReady:       c.SessionReadyHandler,
+
+		PomodoroCompleted: c.PomodoroCompletedHandler,
+	}
+}
+
 func (c *Communicator) SessionFinishedHandler(id domain.ChatID, session *domain.Session, endKind sessionmanager.PomodoroEndKind) {
+	l := c.Localizer()
 	switch endKind {
 	case sessionmanager.PomodoroFinished:
-		c.ReplyAndNotify("Pomodoro done! The session is complete, congratulations!")
+		c.StopLiveStatus(l.T("live_complete"))
+		c.ReplyAndNotify(l.T("session_complete") +
+			interruptionsString(l, session) + c.goalProgressString(id))
 	case sessionmanager.PomodoroCanceled:
-		c.ReplyAndNotify("Session canceled.")
+		c.StopLiveStatus(l.T("live_canceled"))
+		c.ReplyAndNotify(l.T("session_canceled"))
 	}
 }
 
 func (c *Communicator) SessionPausedHandler(id domain.ChatID, session *domain.Session) {
-	c.ReplyAndNotify("Your session has paused.")
+	l := c.Localizer()
+	c.StopLiveStatus(liveStatusText(l, session) + "\n\n" + l.T("live_paused"))
+	c.ReplyWithAndControlsAndNotify(l.T("session_paused"), session.State())
+}
+
+func (c *Communicator) PhaseExtendedHandler(id domain.ChatID, session *domain.Session, err error) {
+	l := c.Localizer()
+	if err != nil {
+		switch e := err.(type) {
+		case domain.ExtensionLimitReached:
+			if e.Left <= 0 {
+				c.ReplyWith(l.T("extend_limit_reached"))
+			} else {
+				c.ReplyWith(l.T("extend_limit", l.Duration(int64(e.Left.Seconds()))))
+			}
+		default:
+			c.ReplyWith(l.T("server_error"))
+		}
+		return
+	}
+
+	c.ReplyWithAndControls(l.T("extended", session.LeftTimeMessage(l)), session.State())
+}
+
+func (c *Communicator) PhaseEndingHandler(id domain.ChatID, session *domain.Session, left time.Duration) {
+	l := c.Localizer()
+	minutes := int64(left.Round(time.Minute).Minutes())
+	if minutes < 1 {
+		minutes = 1
+	}
+	leftStr := l.N("duration_minutes", minutes)
+
+	if session.IsRest() {
+		c.ReplyAndNotify(l.T("phase_ending_rest", leftStr))
+	} else {
+		c.ReplyAndNotify(l.T("phase_ending_sprint", leftStr))
+	}
+}
+
+func (c *Communicator) WarnLead(minutes int) {
+	l := c.Localizer()
+	if minutes == 0 {
+		c.ReplyWith(l.T("warn_lead_off"))
+		return
+	}
+	c.ReplyWith(l.T("warn_lead", l.N("duration_minutes", int64(minutes))))
+}
+
+func (c *Communicator) WarnLeadSet(minutes int) {
+	l := c.Localizer()
+	if minutes == 0 {
+		c.ReplyWith(l.T("warnings_disabled"))
+		return
+	}
+	c.ReplyWith(l.T("warn_lead_set", l.N("duration_minutes", int64(minutes))))
+}
+
+func (c *Communicator) OvertimeBeginHandler(id domain.ChatID, session *domain.Session) {
+	c.StopLiveStatus("")
+	c.ReplyWithAndControlsAndNotify(c.Localizer().T("overtime_begin"), session.State())
+}
+
+func (c *Communicator) BreakTaken(err error) {
+	if err != nil {
+		c.ReplyWith(c.Localizer().T("break_error"))
+	}
+}
+
+func (c *Communicator) SprintRestartedHandler(id domain.ChatID, session *domain.Session) {
+	c.StopLiveStatus("")
+	l := c.Localizer()
+	text := l.T("sprint_restarted", l.Duration(int64(session.GetPomodoroDurationSet().Seconds())))
+	c.ReplyWithAndControlsAndNotify(text, session.State())
+	c.StartLiveStatus(session)
+}
+
+func (c *Communicator) SprintRestarted(err error) {
+	if err != nil {
+		c.ReplyWith(c.Localizer().T("restart_error"))
+	}
+}
+
+func (c *Communicator) InterruptionLoggedHandler(
+	id domain.ChatID,
+	session *domain.Session,
+	interruption domain.Interruption,
+	err error,
+) {
+	if err != nil {
+		c.Interrupted(err)
+		return
+	}
+	l := c.Localizer()
+	c.ReplyWith(l.T("interruption_logged", interruption.Kind.Localized(l), session.GetInterruptions().Localized(l)))
+}
+
+// interruptionsString returns the line of the session summary about the
+// interruptions (empty if there were none).
+func interruptionsString(l *i18n.Localizer, session *domain.Session) string {
+	interruptions := session.GetInterruptions()
+	if interruptions.Total() == 0 {
+		return ""
+	}
+	return l.T("session_interruptions", interruptions.Localized(l))
+}
+
+func (c *Communicator) Interrupted(err error) {
+	if err != nil {
+		c.ReplyWith(c.Localizer().T("interrupt_error"))
+	}
+}
+
+func (c *Communicator) InterruptUsage() {
+	c.ReplyWith(c.Localizer().T("interrupt_usage"))
+}
+
+func (c *Communicator) SessionReadyHandler(id domain.ChatID, session *domain.Session) {
+	c.StopLiveStatus("")
+	l := c.Localizer()
+	text := l.T("session_ready")
+	if _, timeout := data.GetManualAdvance(c.appState, id); timeout > 0 {
+		text += l.T("session_ready_timeout", l.Duration(int64(timeout.Seconds())))
+	}
+	c.ReplyWithAndControlsAndNotify(text, session.State())
+}
+
+func (c *Communicator) NextSprint(err error) {
+	if err != nil {
+		c.ReplyWith(c.Localizer().T("next_error"))
+	}
+}
+
+func (c *Communicator) ManualAdvance(enabled bool, timeout time.Duration) {
+	l := c.Localizer()
+	switch {
+	case !enabled:
+		c.ReplyWith(l.T("manual_off"))
+	case timeout > 0:
+		c.ReplyWith(l.T("manual_on_timeout", l.Duration(int64(timeout.Seconds()))))
+	default:
+		c.ReplyWith(l.T("manual_on"))
+	}
+}
+
+func (c *Communicator) ManualAdvanceSet(enabled bool, timeoutMinutes int) {
+	l := c.Localizer()
+	switch {
+	case !enabled:
+		c.ReplyWith(l.T("manual_set_off"))
+	case timeoutMinutes > 0:
+		c.ReplyWith(l.T("manual_set_on_timeout", l.N("duration_minutes", int64(timeoutMinutes))))
+	default:
+		c.ReplyWith(l.T("manual_set_on"))
+	}
+}
+
+func (c *Communicator) Flow(enabled bool) {
+	if enabled {
+		c.ReplyWith(c.Localizer().T("flow_on"))
+	} else {
+		c.ReplyWith(c.Localizer().T("flow_off"))
+	}
+}
+
+func (c *Communicator) FlowSet(enabled bool) {
+	if enabled {
+		c.ReplyWith(c.Localizer().T("flow_set_on"))
+	} else {
+		c.ReplyWith(c.Localizer().T("flow_set_off"))
+	}
+}
+
+func (c *Communicator) LiveStatus(enabled bool) {
+	if enabled {
+		c.ReplyWith(c.Localizer().T("live_status_on"))
+	} else {
+		c.ReplyWith(c.Localizer().T("live_status_off"))
+	}
+}
+
+func (c *Communicator) LiveStatusSet(enabled bool, session *domain.Session) {
+	if enabled {
+		c.ReplyWith(c.Localizer().T("live_status_set_on"))
+		c.StartLiveStatus(session)
+	} else {
+		c.StopLiveStatus("")
+		c.ReplyWith(c.Localizer().T("live_status_set_off"))
+	}
 }
 
 func (c *Communicator) RestFinishedHandler(id domain.ChatID, session *domain.Session) {
-	text := fmt.Sprintf(
-		"Pomodoro %s started.",
-		utils.NiceTimeFormatting(session.GetPomodoroDurationSet().Seconds()),
-	)
-	c.ReplyWithAndHourglassAndNotify(text)
+	l := c.Localizer()
+	text := l.T("sprint_started", l.Duration(int64(session.GetPomodoroDurationSet().Seconds())))
+	c.ReplyWithAndControlsAndNotify(text, session.State())
+	c.StartLiveStatus(session)
 }
 
 func (c *Communicator) RestBeginHandler(id domain.ChatID, session *domain.Session) {
-	text := fmt.Sprintf(
-		"Pomodoro done! Have rest for %s now.",
-		utils.NiceTimeFormatting(session.GetRestDurationSet().Seconds()),
-	)
+	l := c.Localizer()
+	var text string
+	if session.IsLongRest() {
+		text = l.T("long_rest_begin", l.Duration(int64(session.GetCurrentRestDurationSet().Seconds())))
+	} else {
+		text = l.T("rest_begin", l.Duration(int64(session.GetCurrentRestDurationSet().Seconds())))
+	}
+	if session.IsFlow() && session.GetFocusDuration() > 0 {
+		text += l.T("focused_for",
+			l.Duration(session.GetFocusDuration()),
+			l.Duration(int64(session.GetPomodoroDurationSet().Seconds())),
+		)
+	}
+
+	c.ReplyWithAndControlsAndNotify(text+c.goalProgressString(id), session.State())
+	c.StartLiveStatus(session)
+}
 
-	c.ReplyAndNotify(text)
+// goalProgressString Print the progress of the chat toward its daily goal
+// (if any), to be appended to a message.
+func (c *Communicator) goalProgressString(id domain.ChatID) string {
+	progress, err := data.GetGoalProgress(c.appState, id)
+	if err != nil {
+		log.Printf("[Communicator::goalProgressString] error: %v\n", err.Error())
+		return ""
+	}
+	if !progress.HasGoal() {
+		return ""
+	}
+	return c.Localizer().T("goal_progress", progress.Today, progress.Goal)
+}
+
+func (c *Communicator) PomodoroCompletedHandler(id domain.ChatID, session *domain.Session, progress domain.GoalProgress) {
+	if !progress.JustReached {
+		return
+	}
+
+	l := c.Localizer()
+	text := l.T("goal_reached", progress.Goal)
+	if progress.Streak > 1 {
+		text += l.N("goal_reached_streak", int64(progress.Streak))
+	}
+	c.ReplyWith(text)
+}
+
+func (c *Communicator) Goal(err error, progress domain.GoalProgress) {
+	l := c.Localizer()
+	if err != nil {
+		c.ReplyWith(l.T("server_error"))
+		return
+	}
+	if !progress.HasGoal() {
+		c.ReplyWith(l.T("no_goal"))
+		return
+	}
+
+	text := l.T("goal", progress.Goal, progress.Today, progress.Goal)
+	if progress.Streak > 0 {
+		text += l.N("goal_streak", int64(progress.Streak))
+	}
+	c.ReplyWith(text)
+}
+
+func (c *Communicator) GoalSet(goal int) {
+	if goal == 0 {
+		c.ReplyWith(c.Localizer().T("goal_removed"))
+		return
+	}
+	c.ReplyWith(c.Localizer().T("goal_set", goal))
 }
 
 func (c *Communicator) SessionAlreadyRunning() {
-	c.ReplyWith("A session already running.")
+	c.ReplyWith(c.Localizer().T("session_already_running"))
 }
 
 func (c *Communicator) SessionResumed(err error, session *domain.Session) {
+	l := c.Localizer()
 	if err != nil {
 		if session.IsZero() {
-			c.ReplyWith("Session was not set.")
-		} else if session.IsCanceled() {
-			c.ReplyWith("Last session was canceled.")
-		} else if !session.IsStopped() {
-			c.ReplyWith("Session is already running.")
-		} else {
-			c.ReplyWith("Server error.")
+			c.ReplyWith(l.T("session_not_set"))
+			return
+		}
+		switch session.State() {
+		case domain.StateCanceled:
+			c.ReplyWith(l.T("last_session_canceled"))
+		case domain.StateFinished:
+			c.ReplyWith(l.T("last_session_finished"))
+		case domain.StatePending:
+			c.ReplyWith(l.T("session_not_started"))
+		case domain.StateSprint, domain.StateRest, domain.StateOvertime, domain.StateReady:
+			c.ReplyWith(l.T("session_running"))
+		default:
+			c.ReplyWith(l.T("server_error"))
 		}
 		return
 	}
 
-	c.ReplyWithAndHourglassAndNotify("Session resumed!")
+	c.ReplyWithAndControlsAndNotify(l.T("session_resumed"), session.State())
+	c.StartLiveStatus(session)
 }
 
 func (c *Communicator) OnlyGroupsCommand() {
-	c.ReplyWith("This command works only in groups, sorry.")
+	c.ReplyWith(c.Localizer().T("only_groups"))
+}
+
+func (c *Communicator) OnlyPrivateCommand() {
+	c.ReplyWith(c.Localizer().T("only_private"))
 }
 
 func (c *Communicator) NewSession(session domain.SessionDefaultData) {
-	c.ReplyWith(fmt.Sprintf("New session!\n\n%s", session.String()))
+	l := c.Localizer()
+	c.ReplyWith(l.T("new_session", session.Localized(l)))
 }
 
 func (c *Communicator) Info() {
-	c.ReplyWith("I am a pomodoro bot written in Go!")
+	c.ReplyWith(c.Localizer().T("info"))
 	c.ShowLicenseNotice()
 }
 
 func (c *Communicator) DataCleaned() {
-	c.ReplyWith("Your data has been cleaned.")
+	c.ReplyWith(c.Localizer().T("data_cleaned"))
 }
 
-func (c *Communicator) Help() {
-	c.ReplyWith("Set a session (examples)\n/25for4rest5 --> 4 🍅, 25 minutes + 5m for rest.\n" +
-		"The latter is also achieved with /default.\n" +
-		"/30for4 --> 4 🍅, 30 minutes (default: +5m for rest).\n" +
-		"/25 --> 1 🍅, 25 minutes (single pomodoro sprint)\n" +
-		"/30forXrest7 --> unspecified no. of 🍅s, 30 minutes + 7m for rest.\n\n" +
-		"Other commands:\n" +
-		"(/s) /start_sprint to start (if /autorun is set off)\n" +
-		"(/p) /pause to pause a session in run\n" +
-		"(/c) /cancel to cancel a session\n" +
-		"/resume to resume a paused session.\n" +
-		"(/se) /session to check your session settings and status.\n" +
-		"/reset to reset your profile/chat settings.\n" +
-		"/info to have some info on this bot.")
+// Help Show the session patterns and the commands that can be used in the
+// chat.
+func (c *Communicator) Help(registry *commandRegistry, isAdmin bool) {
+	c.ReplyWith(registry.helpText(c.Localizer(), c.IsGroup, isAdmin))
 }
 
 func (c *Communicator) SessionPaused(err error, session domain.Session) {
 	if err != nil {
-		if !session.IsStopped() {
-			c.ReplyWith("Session was not running.")
-		} else {
-			c.ReplyWith("Server error.")
+		switch err.(type) {
+		case domain.InvalidTransition:
+			c.ReplyWith(c.Localizer().T("session_not_running"))
+		default:
+			c.ReplyWith(c.Localizer().T("server_error"))
 		}
 	}
 }
 
+func (c *Communicator) PhaseSkipped(err error) {
+	if err != nil {
+		c.ReplyWith(c.Localizer().T("session_not_running"))
+	}
+}
+
+func (c *Communicator) PhaseExtended(err error) {
+	if err != nil {
+		c.ReplyWith(c.Localizer().T("session_not_running"))
+	}
+}
+
 func (c *Communicator) SessionCanceled(err error, session domain.Session) {
 	if err != nil {
-		if session.IsStopped() {
-			c.ReplyWith("Session was not running.")
-		} else {
-			c.ReplyWith("Server error.")
+		switch err.(type) {
+		case domain.InvalidTransition:
+			c.ReplyWith(c.Localizer().T("session_not_running"))
+		default:
+			c.ReplyWith(c.Localizer().T("server_error"))
 		}
 	}
 }
 
 func (c *Communicator) SessionState(session domain.Session) {
-	var stateStr = session.State()
+	l := c.Localizer()
 
 	var replyMsgText string
 	if session.IsCanceled() {
-		replyMsgText = fmt.Sprintf("Your session state: %s.", stateStr)
+		replyMsgText = l.T("session_state", session.State().Localized(l))
 	} else {
-		replyMsgText = session.String()
+		replyMsgText = session.Localized(l)
 	}
 	c.ReplyWith(replyMsgText)
 }
 
+func (c *Communicator) Stats(err error, today, week, allTime domain.FocusStats) {
+	l := c.Localizer()
+	if err != nil {
+		switch err.(type) {
+		case domain.PersistenceUnavailable:
+			c.ReplyWith(l.T("stats_unavailable"))
+		default:
+			c.ReplyWith(l.T("server_error"))
+		}
+		return
+	}
+
+	focusStatsString := func(stats domain.FocusStats) string {
+		text := l.T("stats_line", stats.Pomodoros, l.Duration(stats.FocusTime))
+		if stats.Interruptions.Total() > 0 {
+			text += l.T("stats_interruptions", stats.Interruptions.Localized(l))
+		}
+		return text
+	}
+
+	c.ReplyWith(l.T("stats", focusStatsString(today), focusStatsString(week), focusStatsString(allTime)))
+}
+
+func (c *Communicator) SessionPatternError(err error) {
+	c.ReplyWith(c.Localizer().T("session_pattern_error", err.Error()))
+}
+
+func (c *Communicator) PlanError(err error) {
+	c.ReplyWith(c.Localizer().T("plan_error", err.Error()))
+}
+
+func (c *Communicator) PresetUsage() {
+	c.ReplyWith(c.Localizer().T("preset_usage"))
+}
+
+func (c *Communicator) PresetInvalidName(name string) {
+	c.ReplyWith(c.Localizer().T("preset_invalid_name", name))
+}
+
+func (c *Communicator) PresetError(err error) {
+	switch e := err.(type) {
+	case domain.PresetNotFound:
+		c.ReplyWith(c.Localizer().T("preset_not_found", e.Name))
+	default:
+		c.ReplyWith(c.Localizer().T("preset_invalid", err.Error()))
+	}
+}
+
+func (c *Communicator) PresetSaved(err error, name string, session domain.SessionDefaultData) {
+	l := c.Localizer()
+	if err != nil {
+		c.ReplyWith(l.T("server_error"))
+		return
+	}
+	c.ReplyWith(l.T("preset_saved", name, session.Localized(l)))
+}
+
+func (c *Communicator) PresetList(err error, presets []domain.Preset) {
+	l := c.Localizer()
+	if err != nil {
+		c.ReplyWith(l.T("server_error"))
+		return
+	}
+	if len(presets) == 0 {
+		c.ReplyWith(l.T("no_presets"))
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString(l.T("presets"))
+	for _, preset := range presets {
+		builder.WriteString(fmt.Sprintf("\n- %s: %s", preset.Name, preset.Pattern))
+		if preset.IsDefault {
+			builder.WriteString(l.T("preset_default_mark"))
+		}
+	}
+	c.ReplyWith(builder.String())
+}
+
+func (c *Communicator) PresetDeleted(err error, name string) {
+	if err != nil {
+		switch err.(type) {
+		case domain.PresetNotFound:
+			c.PresetError(err)
+		default:
+			c.ReplyWith(c.Localizer().T("server_error"))
+		}
+		return
+	}
+	c.ReplyWith(c.Localizer().T("preset_deleted", name))
+}
+
+func (c *Communicator) PresetDefaultSet(err error, name string) {
+	if err != nil {
+		switch err.(type) {
+		case domain.PresetNotFound:
+			c.PresetError(err)
+		default:
+			c.ReplyWith(c.Localizer().T("server_error"))
+		}
+		return
+	}
+	c.ReplyWith(c.Localizer().T("preset_default_set", name))
+}
+
+func (c *Communicator) ScheduleUsage() {
+	c.ReplyWith(c.Localizer().T("schedule_usage"))
+}
+
+func (c *Communicator) ScheduleError(err error) {
+	c.ReplyWith(c.Localizer().T("schedule_error", err.Error()))
+}
+
+func (c *Communicator) SessionScheduled(err error, schedule domain.SessionSchedule, now time.Time) {
+	l := c.Localizer()
+	if err != nil {
+		switch err.(type) {
+		case domain.TooManySchedules:
+			c.ReplyWith(l.T("too_many_schedules", domain.MaxSchedulesPerChat))
+		default:
+			c.ReplyWith(l.T("server_error"))
+		}
+		return
+	}
+	c.ReplyWith(l.T("session_scheduled",
+		schedule.StringIn(l, now.Location()), l.DateTime(schedule.NextRun(now).In(now.Location()))))
+}
+
+func (c *Communicator) ScheduleList(err error, schedules []domain.SessionSchedule, now time.Time) {
+	l := c.Localizer()
+	if err != nil {
+		c.ReplyWith(l.T("server_error"))
+		return
+	}
+	if len(schedules) == 0 {
+		c.ReplyWith(l.T("no_schedules"))
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString(l.T("schedules"))
+	for _, schedule := range schedules {
+		builder.WriteString("\n- " + schedule.StringIn(l, now.Location()))
+		if schedule.IsRecurring() {
+			builder.WriteString(l.T("schedule_next", l.DateTime(schedule.NextRun(now))))
+		}
+	}
+	c.ReplyWith(builder.String())
+}
+
+func (c *Communicator) SessionUnscheduled(err error, scheduleId int64) {
+	l := c.Localizer()
+	if err != nil {
+		switch err.(type) {
+		case domain.ScheduleNotFound:
+			c.ReplyWith(l.T("schedule_not_found", scheduleId))
+		default:
+			c.ReplyWith(l.T("server_error"))
+		}
+		return
+	}
+	c.ReplyWith(l.T("session_unscheduled", scheduleId))
+}
+
+func (c *Communicator) SessionsUnscheduled(err error) {
+	if err != nil {
+		c.ReplyWith(c.Localizer().T("server_error"))
+		return
+	}
+	c.ReplyWith(c.Localizer().T("sessions_unscheduled"))
+}
+
+func (c *Communicator) ScheduledSessionStarting(schedule domain.SessionSchedule) {
+	c.ReplyAndNotify(c.Localizer().T("scheduled_session_starting", schedule.ID))
+}
+
+func (c *Communicator) ScheduledSessionSkipped(schedule domain.SessionSchedule) {
+	c.ReplyWith(c.Localizer().T("scheduled_session_skipped", schedule.ID))
+}
+
+func (c *Communicator) Timezone(location *time.Location, now time.Time) {
+	c.ReplyWith(c.Localizer().T("timezone", location, now.In(location).Format("15:04")))
+}
+
+func (c *Communicator) TimezoneSet(err error, location *time.Location, now time.Time) {
+	if err != nil {
+		c.ReplyWith(c.Localizer().T("timezone_unknown"))
+		return
+	}
+	c.ReplyWith(c.Localizer().T("timezone_set", location, now.In(location).Format("15:04")))
+}
+
+// Language Show the language the bot speaks in the chat, and the ones it
+// can speak.
+func (c *Communicator) Language(auto bool) {
+	l := c.Localizer()
+	name := c.appState.Messages.Localizer(l.Language()).Name()
+	if auto {
+		name += l.T("language_auto")
+	}
+	c.ReplyWith(l.T("language", name, c.languageCodes()))
+}
+
+// LanguageSet Confirm the language of the chat, in the language itself.
+func (c *Communicator) LanguageSet(auto bool) {
+	if auto {
+		c.ReplyWith(c.Localizer().T("language_set_auto"))
+	} else {
+		c.ReplyWith(c.Localizer().T("language_set"))
+	}
+}
+
+func (c *Communicator) LanguageUnknown(language string) {
+	c.ReplyWith(c.Localizer().T("language_unknown", language, c.languageCodes()))
+}
+
+// languageCodes returns the languages of the bot, e.g. "en English, it
+// Italiano".
+func (c *Communicator) languageCodes() string {
+	var languages []string
+	for _, catalog := range c.appState.Messages.Languages() {
+		languages = append(languages, catalog.Code+" "+catalog.Name)
+	}
+	return strings.Join(languages, ", ")
+}
+
 func (c *Communicator) CommandError() {
-	c.ReplyWith("Command error.")
+	c.ReplyWith(c.Localizer().T("command_error"))
 }
 
 func (c *Communicator) Hourglass() {
-	c.ReplyWithAndHourglass("Here is an hourglass")
+	session := data.GetUserSessionRunning(c.appState, c.ChatID, c.ChatID)
+	c.ReplyWithAndControls(c.Localizer().T("hourglass"), session.State())
 }
 
 func (c *Communicator) ShowPrivacyPolicy() {
-	c.ReplyWithParseMode(c.appVariables.PrivacyPolicy1, "html", true)
+	c.ReplyWithHTML(c.appVariables.PrivacyPolicy1)
 }
 
 func (c *Communicator) PrivacySettingsUpdated() {
-	c.ReplyWith("Your privacy settings have been updated!")
+	c.ReplyWith(c.Localizer().T("privacy_updated"))
 }
 
 func (c *Communicator) ShowLicenseNotice() {
-	c.ReplyWithParseMode(c.appVariables.OpenSource1, "html", true)
+	c.ReplyWithHTML(c.appVariables.OpenSource1)
 }
 
-func (c *Communicator) ErrorSessionTooLong() {
-	tooLongMessages := [...]string{
-		"I don't have time for this, sorry.",
-		"That's too much time for a session that I can manage.",
-		"Bammer. Loooong session.",
-		"The session you specified lasts too long.",
+func (c *Communicator) SessionLimitError(err error) {
+	l := c.Localizer()
+	e, ok := err.(domain.SessionLimitExceeded)
+	if !ok {
+		c.ReplyWith(l.T("limit_invalid_session", err.Error()))
+		return
+	}
+
+	var text string
+	switch e.Limit {
+	case domain.LimitMaxTotal:
+		text = l.T("limit_total_exceeded", l.Duration(e.Value*60), l.Duration(e.Bound*60))
+	case domain.LimitMaxSprint:
+		text = l.T("limit_sprint_exceeded", l.Duration(e.Bound*60), l.Duration(e.Value*60))
+	case domain.LimitMinSprint:
+		text = l.T("limit_min_sprint_exceeded", l.Duration(e.Bound), l.Duration(e.Value))
+	case domain.LimitMaxSprints:
+		text = l.T("limit_sprints_exceeded", l.N("pomodoros", e.Bound), e.Value)
+	case domain.LimitMaxUnspecified:
+		text = l.T("limit_unspecified_exceeded", l.Duration(e.Bound*60))
+	default:
+		text = l.T("limit_invalid_session", err.Error())
 	}
-	rand.Seed(time.Now().UnixNano())
-	c.ReplyWith(tooLongMessages[rand.Intn(len(tooLongMessages))])
+	c.ReplyWith(text + l.T("limit_see_limits"))
+}
+
+// sessionLimitUnitKeys are the plural messages printing the value of each
+// unit of the limits.
+var sessionLimitUnitKeys = map[string]string{
+	"minutes":   "duration_minutes",
+	"seconds":   "duration_seconds",
+	"pomodoros": "pomodoros",
+}
+
+func (c *Communicator) SessionLimits(limits domain.SessionLimits) {
+	l := c.Localizer()
+	text := l.T("limits")
+	for _, limit := range domain.SessionLimitNames {
+		text += fmt.Sprintf("\n%s: %s (%s)",
+			limit, l.N(sessionLimitUnitKeys[limit.Unit()], int64(limits.Get(limit))), l.T("limit_"+string(limit)))
+	}
+	c.ReplyWith(text)
+}
+
+func (c *Communicator) LimitsUsage() {
+	c.ReplyWith(c.Localizer().T("limits_usage"))
+}
+
+func (c *Communicator) LimitsAdminOnly() {
+	c.ReplyWith(c.Localizer().T("limits_admin_only"))
 }
@@ -0,0 +1,270 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/utils"
+	"log"
+	"sync"
+	"time"
+)
+
+// Live status messages are edited at most once per interval in each chat
+// (less often in groups) and, overall, no more than once per
+// liveStatusSpacing, in order to stay within the rate limits of Telegram.
+const (
+	liveStatusInterval      = 30 * time.Second
+	liveStatusGroupInterval = time.Minute
+	liveStatusSpacing       = 50 * time.Millisecond
+	liveStatusBarWidth      = 10
+)
+
+// liveStatus is the status message of a chat that is being kept up to date.
+type liveStatus struct {
+	messageId int
+	stop      chan struct{}
+
+	// editLock serializes the edits of the message, so that the final one
+	// (see StopLiveStatus) is not overwritten by an update in flight.
+	editLock sync.Mutex
+	final    bool
+}
+
+// liveStatusPhase is what the live status message shows of the current sprint
+// (or rest) of a session, as it was when the phase began. The session belongs
+// to its timer goroutine, so the message is kept up to date with this.
+type liveStatusPhase struct {
+	header string
+	total  time.Duration
+	left   time.Duration
+	at     time.Time
+}
+
+func newLiveStatusPhase(l *i18n.Localizer, session *domain.Session) liveStatusPhase {
+	var header string
+	switch {
+	case session.IsRest() && session.IsLongRest():
+		header = l.T("live_long_rest")
+	case session.IsRest():
+		header = l.T("live_rest")
+	case session.IsSprintDurationUnspecified():
+		header = l.T("live_sprint_unspecified", session.CompletedSprints()+1)
+	default:
+		header = l.T("live_sprint", session.CompletedSprints()+1, session.GetSprintDurationSet())
+	}
+
+	return liveStatusPhase{
+		header: header,
+		total:  session.PhaseDurationSet(),
+		left:   session.PhaseTimeLeft(),
+		at:     session.Clock().Now(),
+	}
+}
+
+// timeLeft returns how much of the phase is left at the given time.
+func (p liveStatusPhase) timeLeft(now time.Time) time.Duration {
+	if left := p.left - now.Sub(p.at); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// text Print the phase with the time left at the given time and a progress
+// bar.
+func (p liveStatusPhase) text(l *i18n.Localizer, now time.Time) string {
+	left := p.timeLeft(now)
+
+	return p.header + "\n" +
+		utils.ProgressBar((p.total-left).Seconds(), p.total.Seconds(), liveStatusBarWidth) + "\n" +
+		l.T("live_left", l.Duration(int64(left.Seconds())))
+}
+
+// liveStatusRegistry keeps track of the live status message of each chat.
+//
+// Communicators are created for each update, so the registry is shared by
+// all of them.
+type liveStatusRegistry struct {
+	lock     sync.Mutex
+	messages map[domain.ChatID]*liveStatus
+
+	editLock sync.Mutex
+	lastEdit time.Time
+}
+
+var liveStatuses = &liveStatusRegistry{
+	messages: make(map[domain.ChatID]*liveStatus),
+}
+
+// swap Register the live status message of the chat (nil to remove it),
+// returning the previous one, if any.
+func (r *liveStatusRegistry) swap(chatId domain.ChatID, status *liveStatus) *liveStatus {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	previous := r.messages[chatId]
+	if status == nil {
+		delete(r.messages, chatId)
+	} else {
+		r.messages[chatId] = status
+	}
+	return previous
+}
+
+// remove Unregister the live status message of the chat, if it is still
+// `status`.
+func (r *liveStatusRegistry) remove(chatId domain.ChatID, status *liveStatus) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.messages[chatId] == status {
+		delete(r.messages, chatId)
+	}
+}
+
+// waitEditTurn Block until enough time has passed since the last edit of any
+// live status message.
+func (r *liveStatusRegistry) waitEditTurn(clock domain.Clock) {
+	r.editLock.Lock()
+	defer r.editLock.Unlock()
+
+	if wait := liveStatusSpacing - clock.Now().Sub(r.lastEdit); wait > 0 {
+		<-clock.After(wait)
+	}
+	r.lastEdit = clock.Now()
+}
+
+// StartLiveStatus Send the live status message of the current sprint (or
+// rest) of the session, and keep it up to date until the phase ends or the
+// session stops. The live status message of the previous phase, if any, is
+// no longer updated.
+//
+// Nothing happens if the chat did not enable live status messages, or if the
+// session is waiting for its next sprint.
+func (c *Communicator) StartLiveStatus(session *domain.Session) {
+	if !data.IsLiveStatusEnabled(c.appState, c.ChatID) || session.IsStopped() ||
+		session.State() == domain.StateReady {
+		c.StopLiveStatus("")
+		return
+	}
+
+	l := c.Localizer()
+	phase := newLiveStatusPhase(l, session)
+	text := phase.text(l, phase.at)
+	messageId, err := c.Bot.SendText(c.ChatID, text)
+	if err != nil {
+		log.Printf("[Communicator::StartLiveStatus] error: %v\n", err.Error())
+		return
+	}
+
+	status := &liveStatus{
+		messageId: messageId,
+		stop:      make(chan struct{}),
+	}
+	if previous := liveStatuses.swap(c.ChatID, status); previous != nil {
+		close(previous.stop)
+	}
+
+	interval := liveStatusInterval
+	if c.IsGroup {
+		interval = liveStatusGroupInterval
+	}
+	go c.updateLiveStatus(l, phase, status, text, interval)
+}
+
+// StopLiveStatus Stop updating the live status message of the chat, if any.
+// If finalText is not empty, the message is edited one last time with it.
+func (c *Communicator) StopLiveStatus(finalText string) {
+	status := liveStatuses.swap(c.ChatID, nil)
+	if status == nil {
+		return
+	}
+	close(status.stop)
+
+	if finalText != "" {
+		c.editLiveStatus(status, finalText, true)
+	}
+}
+
+// updateLiveStatus Edit the live status message with the time left in the
+// phase, every interval, until it is stopped or the phase ends.
+func (c *Communicator) updateLiveStatus(
+	l *i18n.Localizer,
+	phase liveStatusPhase,
+	status *liveStatus,
+	lastText string,
+	interval time.Duration,
+) {
+	defer liveStatuses.remove(c.ChatID, status)
+
+	clock := c.appState.GetClock()
+	for {
+		select {
+		case <-status.stop:
+			return
+		case <-clock.After(interval):
+		}
+
+		now := clock.Now()
+		if phase.timeLeft(now) <= 0 {
+			// The phase is over: a new live status message (if any) is sent
+			// for the next one.
+			return
+		}
+
+		// Telegram refuses edits that leave the message unchanged.
+		text := phase.text(l, now)
+		if text == lastText {
+			continue
+		}
+		c.editLiveStatus(status, text, false)
+		lastText = text
+	}
+}
+
+// editLiveStatus Edit the live status message with the text, unless its
+// final edit has been done already. No edits follow the final one.
+func (c *Communicator) editLiveStatus(status *liveStatus, text string, final bool) {
+	status.editLock.Lock()
+	defer status.editLock.Unlock()
+
+	if status.final {
+		return
+	}
+	if !final {
+		select {
+		case <-status.stop:
+			return
+		default:
+		}
+	}
+	status.final = final
+
+	liveStatuses.waitEditTurn(c.appState.GetClock())
+
+	if err := c.Bot.EditText(c.ChatID, status.messageId, text); err != nil {
+		log.Printf("[Communicator::editLiveStatus] error: %v\n", err.Error())
+	}
+}
+
+// liveStatusText Print the current sprint (or rest) of the session with the
+// time left and a progress bar.
+func liveStatusText(l *i18n.Localizer, session *domain.Session) string {
+	phase := newLiveStatusPhase(l, session)
+	return phase.text(l, phase.at)
+}
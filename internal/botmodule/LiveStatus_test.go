@@ -0,0 +1,104 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/messenger"
+	"GoforPomodoro/internal/sessionmanager"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForEdits Block until the first message sent to the chat has been
+// edited the given number of times, returning it.
+func waitForEdits(t *testing.T, bot *messenger.Recorder, chatId domain.ChatID, edits int) messenger.RecordedMessage {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		if messages := bot.Messages(chatId); len(messages) > 0 && messages[0].Edits >= edits {
+			return messages[0]
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the live status message was never edited %d times", edits)
+		}
+	}
+}
+
+func TestLiveStatus(t *testing.T) {
+	l := i18n.English()
+
+	tests := []struct {
+		name     string
+		stop     func(c *Communicator, session *domain.Session)
+		expected string
+	}{
+		{"pause", func(c *Communicator, session *domain.Session) {
+			_ = session.Pause()
+			c.SessionPausedHandler(testUser, session)
+		}, l.T("live_paused")},
+		{"cancel", func(c *Communicator, session *domain.Session) {
+			_ = session.Cancel()
+			c.SessionFinishedHandler(testUser, session, sessionmanager.PomodoroCanceled)
+		}, l.T("live_canceled")},
+		{"finish", func(c *Communicator, session *domain.Session) {
+			c.SessionFinishedHandler(testUser, session, sessionmanager.PomodoroFinished)
+		}, l.T("live_complete")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bot := messenger.NewRecorder()
+			appState := newTestAppState()
+			clock := appState.GetClock().(*domain.FakeClock)
+			// The edits of the previous tests happened at the same times.
+			liveStatuses.lastEdit = time.Time{}
+
+			data.SetLiveStatus(appState, testUser, true)
+			c := GetCommunicator(appState, &domain.AppVariables{}, testUser, bot)
+
+			session := domain.DefaultSession().ToInitData().ToSession().SetClock(clock)
+			if err := session.Start(); err != nil {
+				t.Fatal(err)
+			}
+			c.StartLiveStatus(session)
+			if got := bot.Messages(testUser)[0].Text; !strings.Contains(got, l.T("live_left", l.Duration(25*60))) {
+				t.Fatalf("the live status should show 25 minutes left: %q", got)
+			}
+
+			<-clock.Sleepers(1)
+			clock.Advance(3 * time.Minute)
+			if got := waitForEdits(t, bot, testUser, 1).Text; !strings.Contains(got, l.T("live_left", l.Duration(22*60))) {
+				t.Fatalf("the live status should show 22 minutes left: %q", got)
+			}
+
+			clock.Advance(liveStatusSpacing)
+			test.stop(c, session)
+			if got := waitForEdits(t, bot, testUser, 2).Text; !strings.Contains(got, test.expected) {
+				t.Fatalf("the live status should end with %q: %q", test.expected, got)
+			}
+
+			// The message is no longer updated.
+			clock.Advance(liveStatusInterval)
+			time.Sleep(10 * time.Millisecond)
+			if edits := bot.Messages(testUser)[0].Edits; edits != 2 {
+				t.Fatalf("the live status was edited %d times", edits)
+			}
+		})
+	}
+}
@@ -18,14 +18,14 @@ package botmodule
 import (
 	"GoforPomodoro/internal/data"
 	"GoforPomodoro/internal/domain"
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"GoforPomodoro/internal/messenger"
 	"log"
 )
 
 func RestoreSessions(
 	appState *domain.AppState,
 	appVariables *domain.AppVariables,
-	bot *tgbotapi.BotAPI,
+	bot messenger.Messenger,
 ) {
 	if appState.PersistenceManager != nil {
 		pairs, err := appState.PersistenceManager.GetActiveChatSettings()
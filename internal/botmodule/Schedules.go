@@ -0,0 +1,266 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/inputprocess"
+	"GoforPomodoro/internal/messenger"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// scheduleKey identifies a scheduled start in domain.Scheduler.
+type scheduleKey struct {
+	chatId     domain.ChatID
+	scheduleId int64
+}
+
+// RestoreSchedules Arm again the scheduled starts of all the chats, e.g.
+// after a restart of the bot.
+func RestoreSchedules(appState *domain.AppState) {
+	schedules, err := data.GetAllSchedules(appState)
+	if err != nil {
+		log.Printf("[Schedules::RestoreSchedules] error: %v\n", err.Error())
+		return
+	}
+
+	log.Printf("[Schedules::RestoreSchedules] #schedules to restore: %v\n", len(schedules))
+	for _, schedule := range schedules {
+		armSchedule(appState, schedule)
+	}
+}
+
+// armSchedule Schedule the next start of the session.
+//
+// One-off starts that were missed by more than domain.ScheduleGracePeriod
+// are dropped.
+func armSchedule(appState *domain.AppState, schedule domain.SessionSchedule) {
+	now := appState.GetClock().Now().In(data.GetLocation(appState, schedule.ChatID))
+
+	at := schedule.NextRun(now)
+	if !schedule.IsRecurring() && at.Before(now.Add(-domain.ScheduleGracePeriod)) {
+		log.Printf("[Schedules::armSchedule] dropping missed schedule %d of chat %d\n", schedule.ID, schedule.ChatID)
+		if err := data.DeleteSchedule(appState, schedule.ChatID, schedule.ID); err != nil {
+			log.Printf("[Schedules::armSchedule] error: %v\n", err.Error())
+		}
+		return
+	}
+
+	appState.Scheduler.Schedule(
+		scheduleKey{chatId: schedule.ChatID, scheduleId: schedule.ID},
+		at,
+		func() {
+			// The scheduler expects callbacks to return quickly, and the
+			// session must not be started while an update of the chat is
+			// being handled: CommandMenuLoop starts it.
+			select {
+			case appState.ScheduledStarts <- schedule:
+			default:
+				log.Printf("[PROBLEM] too many scheduled starts, dropping schedule %d of chat %d\n", schedule.ID, schedule.ChatID)
+			}
+		},
+	)
+}
+
+// disarmSchedule Prevent the scheduled start from firing.
+func disarmSchedule(appState *domain.AppState, chatId domain.ChatID, scheduleId int64) {
+	appState.Scheduler.Unschedule(scheduleKey{chatId: chatId, scheduleId: scheduleId})
+}
+
+// fireSchedule Start the scheduled session, unless the chat has a session
+// going on already (running, paused or waiting for the next sprint), and arm
+// the next start (if any). It runs on the update loop (see armSchedule).
+func fireSchedule(
+	appState *domain.AppState,
+	appVariables *domain.AppVariables,
+	bot messenger.Messenger,
+	schedule domain.SessionSchedule,
+) {
+	chatId := schedule.ChatID
+	senderId := schedule.SenderID
+
+	if !scheduleExists(appState, chatId, schedule.ID) {
+		// It was deleted while firing.
+		return
+	}
+
+	if schedule.IsRecurring() {
+		armSchedule(appState, schedule)
+	} else if err := data.DeleteSchedule(appState, chatId, schedule.ID); err != nil {
+		log.Printf("[Schedules::fireSchedule] error: %v\n", err.Error())
+	}
+
+	communicator := GetCommunicator(appState, appVariables, chatId, bot)
+
+	session := data.GetUserSessionRunning(appState, chatId, senderId)
+	if state := session.State(); state != domain.StatePending && !state.IsOver() {
+		communicator.ScheduledSessionSkipped(schedule)
+		return
+	}
+
+	sessionData := data.GetDefaultUserSession(appState, chatId)
+	if schedule.Pattern != "" {
+		var err error
+		sessionData, err = inputprocess.ParsePresetPattern(schedule.Pattern)
+		if err != nil {
+			log.Printf("[Schedules::fireSchedule] invalid pattern %q: %v\n", schedule.Pattern, err.Error())
+			return
+		}
+	}
+
+	// The limits of the chat may have changed since the session was scheduled.
+	if _, err := inputprocess.ValidateSessionParsed(sessionData, data.GetSessionLimits(appState, chatId)); err != nil {
+		communicator.SessionLimitError(err)
+		return
+	}
+	if schedule.Pattern != "" {
+		data.UpdateDefaultUserSession(appState, chatId, senderId, sessionData)
+	}
+
+	communicator.ScheduledSessionStarting(schedule)
+	ActionStartSprint(senderId, chatId, appState, communicator)
+}
+
+// scheduleExists returns true if the chat still has the scheduled start.
+func scheduleExists(appState *domain.AppState, chatId domain.ChatID, scheduleId int64) bool {
+	schedules, err := data.GetSchedules(appState, chatId)
+	if err != nil {
+		log.Printf("[Schedules::scheduleExists] error: %v\n", err.Error())
+		return false
+	}
+
+	for _, schedule := range schedules {
+		if schedule.ID == scheduleId {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionSchedule Schedule a session of the chat, once (/at 09:00 25for4) or on
+// recurring days (/every weekday 09:00 25for4). The session pattern is
+// optional: without it, the session set in the chat at that time is started.
+func ActionSchedule(
+	senderId domain.ChatID,
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+	recurring bool,
+	parameters []string,
+) {
+	recurrence := domain.RecurrenceOnce
+	if recurring {
+		if len(parameters) == 0 {
+			communicator.ScheduleUsage()
+			return
+		}
+		var err error
+		recurrence, err = domain.ParseScheduleRecurrence(parameters[0])
+		if err != nil {
+			communicator.ScheduleError(err)
+			return
+		}
+		parameters = parameters[1:]
+	}
+
+	if len(parameters) == 0 {
+		communicator.ScheduleUsage()
+		return
+	}
+	hour, minute, err := inputprocess.ParseTimeOfDay(parameters[0])
+	if err != nil {
+		communicator.ScheduleError(err)
+		return
+	}
+
+	pattern := strings.Join(parameters[1:], " ")
+	if pattern != "" {
+		sessionData, err := inputprocess.ParsePresetPattern(pattern)
+		if err != nil {
+			communicator.ScheduleError(err)
+			return
+		}
+		if _, err := inputprocess.ValidateSessionParsed(sessionData, data.GetSessionLimits(appState, chatId)); err != nil {
+			communicator.SessionLimitError(err)
+			return
+		}
+	}
+
+	schedule := domain.SessionSchedule{
+		ChatID:     chatId,
+		SenderID:   senderId,
+		Recurrence: recurrence,
+		Hour:       hour,
+		Minute:     minute,
+		Pattern:    pattern,
+	}
+	now := appState.GetClock().Now().In(data.GetLocation(appState, chatId))
+	if !recurring {
+		schedule.At = domain.NextTimeOfDay(now, hour, minute)
+	}
+
+	schedule, err = data.AddSchedule(appState, schedule)
+	if err == nil {
+		armSchedule(appState, schedule)
+	}
+	communicator.SessionScheduled(err, schedule, now)
+}
+
+// ActionUnschedule Delete a scheduled start of the chat, or all of them.
+func ActionUnschedule(
+	chatId domain.ChatID,
+	appState *domain.AppState,
+	communicator *Communicator,
+	parameters []string,
+) {
+	if len(parameters) == 0 {
+		communicator.ScheduleUsage()
+		return
+	}
+
+	if strings.ToLower(parameters[0]) == "all" {
+		communicator.SessionsUnscheduled(ActionUnscheduleAll(chatId, appState))
+		return
+	}
+
+	scheduleId, err := strconv.ParseInt(strings.TrimPrefix(parameters[0], "#"), 10, 64)
+	if err != nil {
+		communicator.ScheduleUsage()
+		return
+	}
+
+	disarmSchedule(appState, chatId, scheduleId)
+	communicator.SessionUnscheduled(data.DeleteSchedule(appState, chatId, scheduleId), scheduleId)
+}
+
+// ActionUnscheduleAll Delete all the scheduled starts of the chat.
+func ActionUnscheduleAll(chatId domain.ChatID, appState *domain.AppState) error {
+	schedules, err := data.GetSchedules(appState, chatId)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		disarmSchedule(appState, chatId, schedule.ID)
+		if err := data.DeleteSchedule(appState, chatId, schedule.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
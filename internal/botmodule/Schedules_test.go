@@ -0,0 +1,157 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package botmodule
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
+	"GoforPomodoro/internal/messenger"
+	"testing"
+	"time"
+)
+
+func TestFireSchedule(t *testing.T) {
+	english := i18n.English()
+
+	cases := []struct {
+		state   domain.SessionState
+		started bool
+	}{
+		{domain.StatePending, true},
+		{domain.StateSprint, false},
+		{domain.StatePausedSprint, false},
+		{domain.StatePausedRest, false},
+		{domain.StateReady, false},
+		{domain.StateCanceled, true},
+		{domain.StateFinished, true},
+	}
+	for _, c := range cases {
+		t.Run(c.state.Key(), func(t *testing.T) {
+			bot := messenger.NewRecorder()
+			appState := newTestAppState()
+
+			sessionData := domain.DefaultSession().ToInitData()
+			sessionData.State = c.state
+			data.GetUserSessionRunning(appState, testUser, testUser)
+			appState.ReadSettings(testUser).SessionRunning = sessionData.ToSession().
+				SetClock(appState.GetClock()).
+				InitChannel()
+
+			schedule, err := data.AddSchedule(appState, domain.SessionSchedule{
+				ChatID:     testUser,
+				SenderID:   testUser,
+				Recurrence: domain.RecurrenceOnce,
+				Hour:       9,
+				At:         appState.GetClock().Now(),
+				Pattern:    "25for2",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fireSchedule(appState, &domain.AppVariables{}, bot, schedule)
+
+			session := appState.ReadSettings(testUser).SessionRunning
+			if c.started {
+				if session.State() != domain.StateSprint || session.GetSprintDurationSet() != 2 {
+					t.Errorf("the scheduled session should have started: %v, %d sprints",
+						session.State(), session.GetSprintDurationSet())
+				}
+				return
+			}
+			if got := lastText(bot, testUser); got != english.T("scheduled_session_skipped", schedule.ID) {
+				t.Errorf("the scheduled session should have been skipped: %q", got)
+			}
+			if session.State() != c.state {
+				t.Errorf("the session went from %v to %v", c.state, session.State())
+			}
+		})
+	}
+}
+
+func TestScheduledStartsRunOnTheUpdateLoop(t *testing.T) {
+	bot := messenger.NewRecorder()
+	appState := newTestAppState()
+	settings := &domain.AppSettings{BotName: "go4pom_bot"}
+
+	schedule, err := data.AddSchedule(appState, domain.SessionSchedule{
+		ChatID:     testUser,
+		SenderID:   testUser,
+		Recurrence: domain.RecurrenceOnce,
+		Hour:       9,
+		At:         appState.GetClock().Now(),
+		Pattern:    "25for2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		CommandMenuLoop(settings, &domain.AppVariables{}, appState, bot, bot)
+	}()
+
+	// The schedule is due already: the loop takes the start in between two
+	// updates.
+	armSchedule(appState, schedule)
+	for deadline := time.Now().Add(time.Second); len(bot.Messages(testUser)) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the scheduled session never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	bot.Close()
+	<-done
+
+	if got := bot.Messages(testUser)[0].Text; got != i18n.English().T("scheduled_session_starting", schedule.ID) {
+		t.Errorf("the scheduled session should have started: %q", got)
+	}
+	if session := appState.ReadSettings(testUser).SessionRunning; session.State() != domain.StateSprint {
+		t.Errorf("the scheduled session is %v", session.State())
+	}
+}
+
+func TestFireScheduleChecksLimits(t *testing.T) {
+	bot := messenger.NewRecorder()
+	appState := newTestAppState()
+
+	schedule, err := data.AddSchedule(appState, domain.SessionSchedule{
+		ChatID:     testUser,
+		SenderID:   testUser,
+		Recurrence: domain.RecurrenceOnce,
+		Hour:       9,
+		At:         appState.GetClock().Now(),
+		Pattern:    "25for4",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The limits changed after the session was scheduled.
+	data.SetLimitsOverride(appState, testUser, domain.SessionLimits{MaxSprints: 2})
+
+	fireSchedule(appState, &domain.AppVariables{}, bot, schedule)
+
+	english := i18n.English()
+	expected := english.T("limit_sprints_exceeded", english.N("pomodoros", 2), 4) + english.T("limit_see_limits")
+	if got := lastText(bot, testUser); got != expected {
+		t.Errorf("the limits should be reported: %q", got)
+	}
+	if session := data.GetUserSessionRunning(appState, testUser, testUser); session.State() != domain.StatePending {
+		t.Errorf("the scheduled session should not have started: %v", session.State())
+	}
+}
@@ -18,9 +18,12 @@ package data
 import (
 	"GoforPomodoro/internal/data/persistence"
 	"GoforPomodoro/internal/domain"
+	"GoforPomodoro/internal/i18n"
 	"GoforPomodoro/internal/utils"
 	"github.com/BurntSushi/toml"
 	"log"
+	"sort"
+	"time"
 )
 
 func PreloadUsersSettings(
@@ -34,6 +37,10 @@ func PreloadUsersSettings(
 		chatId := pair.First
 		settings := pair.Second
 
+		if settings.SessionRunning != nil {
+			settings.SessionRunning.SetClock(appState.GetClock()).SetLocation(settings.Location())
+		}
+
 		appState.UsersSettings[chatId] = settings
 	}
 }
@@ -52,13 +59,28 @@ func LoadAppVariables() (*domain.AppVariables, error) {
 	return appVariables, err
 }
 
+// LocalesDir is where the catalogs of messages that add (or change)
+// languages of the bot are read from, next to appvariables.toml.
+const LocalesDir = "locales"
+
+// LoadLocales Add to the messages of the bot the catalogs in LocalesDir.
+func LoadLocales(appState *domain.AppState) error {
+	return appState.Messages.LoadDir(LocalesDir)
+}
+
 func LoadAppState(persistenceManager persistence.Manager, debugMode bool) (*domain.AppState, error) {
 	appState := new(domain.AppState)
 
 	appState.DebugMode = debugMode
 
+	appState.Clock = domain.SystemClock{}
+
+	appState.Messages = i18n.NewBundle()
+
 	appState.PersistenceManager = persistenceManager
 
+	appState.ScheduledStarts = make(chan domain.SessionSchedule, domain.MaxPendingScheduledStarts)
+
 	appState.UsersSettingsLock.Lock()
 	appState.UsersSettings = make(map[domain.ChatID]*domain.Settings)
 	appState.UsersSettingsLock.Unlock()
@@ -85,6 +107,9 @@ func defaultUserSettingsIfNeeded(appState *domain.AppState, chatId domain.ChatID
 				chatSettings.Autorun = true
 				appState.WriteSettings(chatId, chatSettings)
 			} else { // err == nil
+				if chatSettings.SessionRunning != nil {
+					chatSettings.SessionRunning.SetClock(appState.GetClock()).SetLocation(chatSettings.Location())
+				}
 				appState.WriteSettings(chatId, chatSettings)
 			}
 
@@ -235,6 +260,39 @@ func SetUserAutorun(appState *domain.AppState, chatId domain.ChatID, senderId do
 	}
 }
 
+// SetTimezone sets the timezone of the chat, given its IANA name (e.g.
+// "Europe/Rome").
+func SetTimezone(appState *domain.AppState, chatId domain.ChatID, name string) error {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	location, err := domain.LoadTimezone(name)
+	if err != nil {
+		return err
+	}
+
+	chatSettings := appState.ReadSettings(chatId)
+
+	chatSettings.Timezone = location.String()
+	if chatSettings.SessionRunning != nil {
+		chatSettings.SessionRunning.SetLocation(location)
+	}
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::SetTimezone] error in storing. (%v)\n", err.Error())
+		}
+	}
+	return nil
+}
+
+// GetLocation returns the location of the timezone of the chat.
+func GetLocation(appState *domain.AppState, chatId domain.ChatID) *time.Location {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	return appState.ReadSettings(chatId).Location()
+}
+
 func GetUserAutorun(appState *domain.AppState, chatId domain.ChatID, senderId domain.ChatID) bool {
 	defaultUserSettingsIfNeeded(appState, chatId)
 
@@ -271,13 +329,22 @@ func UpdateDefaultUserSession(appState *domain.AppState, chatId domain.ChatID, s
 	}
 }
 
+// GetDefaultUserSession returns the session the chat starts when it is not
+// told which one.
+func GetDefaultUserSession(appState *domain.AppState, chatId domain.ChatID) domain.SessionDefaultData {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	return appState.ReadSettings(chatId).SessionDefault
+}
+
 func GetUserSessionFromSettings(appState *domain.AppState, chatId domain.ChatID, senderId domain.ChatID) domain.SessionInitData {
 	defaultUserSettingsIfNeeded(appState, chatId)
 
-	session := &appState.ReadSettings(chatId).SessionDefault
+	settings := appState.ReadSettings(chatId)
 
-	sData := session.ToInitData()
-	sData.IsPaused = true
+	sData := settings.SessionDefault.ToInitData()
+	sData.Flow = settings.Flow
+	sData.State = domain.StatePending
 
 	return sData // this instantiates a new session object
 }
@@ -290,12 +357,15 @@ func GetNewUserSessionRunning(appState *domain.AppState, chatId domain.ChatID, s
 	sessionDef.PomodoroDuration = sessionDef.PomodoroDurationSet
 	sessionDef.SprintDuration = sessionDef.SprintDurationSet
 	sessionDef.RestDuration = sessionDef.RestDurationSet
-	sessionDef.IsPaused = true
-
-	sessionRunning := sessionDef.ToSession().InitChannel()
+	sessionDef.State = domain.StatePending
 
 	settings := appState.ReadSettings(chatId)
 
+	sessionRunning := sessionDef.ToSession().
+		SetClock(appState.GetClock()).
+		SetLocation(settings.Location()).
+		InitChannel()
+
 	settings.SessionRunning = sessionRunning
 
 	return sessionRunning
@@ -315,9 +385,12 @@ func GetUserSessionRunning(appState *domain.AppState, chatId domain.ChatID, send
 		sessionDef.SprintDuration = sessionDef.SprintDurationSet
 		sessionDef.RestDuration = sessionDef.RestDurationSet
 
-		sessionDef.IsPaused = true
+		sessionDef.State = domain.StatePending
 
-		sessionRunning = sessionDef.ToSession().InitChannel()
+		sessionRunning = sessionDef.ToSession().
+			SetClock(appState.GetClock()).
+			SetLocation(appState.ReadSettings(chatId).Location()).
+			InitChannel()
 
 		appState.ReadSettings(chatId).SessionRunning = sessionRunning
 
@@ -345,6 +418,428 @@ func GetUserSessionRunning(appState *domain.AppState, chatId domain.ChatID, send
 	return sessionRunning
 }
 
+func AddHistoryRecord(appState *domain.AppState, record domain.HistoryRecord) {
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.AddHistoryRecord(record)
+		if err != nil {
+			log.Printf("[DataModel::AddHistoryRecord] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+func AddInterruption(appState *domain.AppState, interruption domain.Interruption) {
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.AddInterruption(interruption)
+		if err != nil {
+			log.Printf("[DataModel::AddInterruption] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+// GetFocusStats returns the statistics of the chat for today, this week and
+// all-time (in this order).
+func GetFocusStats(appState *domain.AppState, chatId domain.ChatID) (
+	today domain.FocusStats,
+	week domain.FocusStats,
+	allTime domain.FocusStats,
+	err error,
+) {
+	if appState.PersistenceManager == nil {
+		err = domain.PersistenceUnavailable{}
+		return
+	}
+
+	now := appState.GetClock().Now().In(GetLocation(appState, chatId))
+
+	if today, err = appState.PersistenceManager.GetFocusStats(chatId, utils.StartOfDay(now)); err != nil {
+		return
+	}
+	if week, err = appState.PersistenceManager.GetFocusStats(chatId, utils.StartOfWeek(now)); err != nil {
+		return
+	}
+	allTime, err = appState.PersistenceManager.GetFocusStats(chatId, time.Time{})
+	return
+}
+
+// GetPresets returns the presets of the chat, sorted by name.
+func GetPresets(appState *domain.AppState, chatId domain.ChatID) ([]domain.Preset, error) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	if appState.PersistenceManager == nil {
+		presets := append([]domain.Preset{}, appState.ReadSettings(chatId).Presets...)
+		sort.Slice(presets, func(i, j int) bool {
+			return presets[i].Name < presets[j].Name
+		})
+		return presets, nil
+	}
+
+	return appState.PersistenceManager.GetPresets(chatId)
+}
+
+// GetPreset returns the preset of the chat with the given name, or
+// domain.PresetNotFound.
+func GetPreset(appState *domain.AppState, chatId domain.ChatID, name string) (domain.Preset, error) {
+	presets, err := GetPresets(appState, chatId)
+	if err != nil {
+		return domain.Preset{}, err
+	}
+
+	for _, preset := range presets {
+		if preset.Name == name {
+			return preset, nil
+		}
+	}
+	return domain.Preset{}, domain.PresetNotFound{Name: name}
+}
+
+// GetDefaultPreset returns the preset started by /default, if the chat set one.
+func GetDefaultPreset(appState *domain.AppState, chatId domain.ChatID) utils.Optional[domain.Preset] {
+	presets, err := GetPresets(appState, chatId)
+	if err != nil {
+		log.Printf("[DataModel::GetDefaultPreset] error in reading. (%v)\n", err.Error())
+		return utils.OptionalOfNil[domain.Preset]()
+	}
+
+	for _, preset := range presets {
+		if preset.IsDefault {
+			return utils.OptionalOf(preset)
+		}
+	}
+	return utils.OptionalOfNil[domain.Preset]()
+}
+
+// StorePreset saves the preset, replacing the one with the same name (if
+// any). If the preset is the default one, the others stop being so.
+func StorePreset(appState *domain.AppState, chatId domain.ChatID, preset domain.Preset) error {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	if appState.PersistenceManager != nil {
+		return appState.PersistenceManager.StorePreset(chatId, preset)
+	}
+
+	settings := appState.ReadSettings(chatId)
+
+	var presets []domain.Preset
+	for _, other := range settings.Presets {
+		if other.Name == preset.Name {
+			continue
+		}
+		if preset.IsDefault {
+			other.IsDefault = false
+		}
+		presets = append(presets, other)
+	}
+	settings.Presets = append(presets, preset)
+
+	return nil
+}
+
+// DeletePreset deletes the preset of the chat with the given name, or returns
+// domain.PresetNotFound.
+func DeletePreset(appState *domain.AppState, chatId domain.ChatID, name string) error {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	if appState.PersistenceManager != nil {
+		return appState.PersistenceManager.DeletePreset(chatId, name)
+	}
+
+	settings := appState.ReadSettings(chatId)
+
+	for i, preset := range settings.Presets {
+		if preset.Name == name {
+			settings.Presets = append(settings.Presets[:i], settings.Presets[i+1:]...)
+			return nil
+		}
+	}
+	return domain.PresetNotFound{Name: name}
+}
+
+// GetSchedules returns the scheduled starts of the chat, sorted by ID.
+func GetSchedules(appState *domain.AppState, chatId domain.ChatID) ([]domain.SessionSchedule, error) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	if appState.PersistenceManager == nil {
+		return append([]domain.SessionSchedule{}, appState.ReadSettings(chatId).Schedules...), nil
+	}
+
+	return appState.PersistenceManager.GetSchedules(chatId)
+}
+
+// GetAllSchedules returns the scheduled starts of all the chats that are
+// persisted (none without persistence).
+func GetAllSchedules(appState *domain.AppState) ([]domain.SessionSchedule, error) {
+	if appState.PersistenceManager == nil {
+		return nil, nil
+	}
+
+	return appState.PersistenceManager.GetAllSchedules()
+}
+
+// AddSchedule stores a new scheduled start and returns it with its ID.
+// domain.TooManySchedules is returned if the chat has already
+// domain.MaxSchedulesPerChat of them.
+func AddSchedule(appState *domain.AppState, schedule domain.SessionSchedule) (domain.SessionSchedule, error) {
+	chatId := schedule.ChatID
+
+	schedules, err := GetSchedules(appState, chatId)
+	if err != nil {
+		return schedule, err
+	}
+	if len(schedules) >= domain.MaxSchedulesPerChat {
+		return schedule, domain.TooManySchedules{}
+	}
+
+	if appState.PersistenceManager != nil {
+		schedule.ID, err = appState.PersistenceManager.AddSchedule(schedule)
+		return schedule, err
+	}
+
+	schedule.ID = 1
+	for _, other := range schedules {
+		if other.ID >= schedule.ID {
+			schedule.ID = other.ID + 1
+		}
+	}
+	settings := appState.ReadSettings(chatId)
+	settings.Schedules = append(settings.Schedules, schedule)
+
+	return schedule, nil
+}
+
+// DeleteSchedule deletes the scheduled start of the chat with the given ID,
+// or returns domain.ScheduleNotFound.
+func DeleteSchedule(appState *domain.AppState, chatId domain.ChatID, scheduleId int64) error {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	if appState.PersistenceManager != nil {
+		return appState.PersistenceManager.DeleteSchedule(chatId, scheduleId)
+	}
+
+	settings := appState.ReadSettings(chatId)
+
+	for i, schedule := range settings.Schedules {
+		if schedule.ID == scheduleId {
+			settings.Schedules = append(settings.Schedules[:i], settings.Schedules[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ScheduleNotFound{ID: scheduleId}
+}
+
+// SetDailyGoal sets how many pomodoros the chat wants to complete each day
+// (0 to unset the goal).
+func SetDailyGoal(appState *domain.AppState, chatId domain.ChatID, goal int) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	chatSettings := appState.ReadSettings(chatId)
+
+	chatSettings.DailyGoal = goal
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::SetDailyGoal] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+// SetFlow sets whether the sessions of the chat are in flow mode. It applies
+// from the next session on, whichever pattern or preset it is started from.
+func SetFlow(appState *domain.AppState, chatId domain.ChatID, enabled bool) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	chatSettings := appState.ReadSettings(chatId)
+
+	chatSettings.Flow = enabled
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::SetFlow] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+// IsFlowEnabled returns whether the sessions of the chat are in flow mode.
+func IsFlowEnabled(appState *domain.AppState, chatId domain.ChatID) bool {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	return appState.ReadSettings(chatId).Flow
+}
+
+// SetManualAdvance sets whether, after each rest, the next sprint of the chat
+// starts only once someone confirms it, and after how many minutes without
+// confirmation the session is paused (0 to wait forever).
+func SetManualAdvance(appState *domain.AppState, chatId domain.ChatID, enabled bool, timeoutMinutes int) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	chatSettings := appState.ReadSettings(chatId)
+
+	chatSettings.ManualAdvance = enabled
+	chatSettings.ReadyTimeoutMinutes = timeoutMinutes
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::SetManualAdvance] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+// GetManualAdvance returns whether the next sprints of the chat start only
+// once confirmed, and how long they are waited for before pausing the session.
+func GetManualAdvance(appState *domain.AppState, chatId domain.ChatID) (bool, time.Duration) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	settings := appState.ReadSettings(chatId)
+	return settings.ManualAdvance, settings.ReadyTimeout()
+}
+
+// GetSessionLimits returns the limits of the sessions of the chat: the ones
+// of the bot, overridden by the ones of the chat (if any), with
+// domain.DefaultSessionLimits in place of the unset ones.
+func GetSessionLimits(appState *domain.AppState, chatId domain.ChatID) domain.SessionLimits {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	limits := appState.SessionLimits.Override(appState.ReadSettings(chatId).LimitsOverride)
+	return limits.WithDefaults()
+}
+
+// SetLimitsOverride sets the limits that replace, for the chat, the ones of
+// the bot (a zero value removes the override).
+func SetLimitsOverride(appState *domain.AppState, chatId domain.ChatID, limits domain.SessionLimits) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	chatSettings := appState.ReadSettings(chatId)
+
+	chatSettings.LimitsOverride = limits
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::SetLimitsOverride] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+// SetWarnLead sets how many minutes before the end of each sprint (or rest)
+// the chat is warned (0 disables the warnings).
+func SetWarnLead(appState *domain.AppState, chatId domain.ChatID, minutes int) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	chatSettings := appState.ReadSettings(chatId)
+
+	chatSettings.WarnLeadMinutes = minutes
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::SetWarnLead] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+// GetWarnLead returns how long before the end of each sprint (or rest) the
+// chat is warned (0 if the chat does not want to be warned).
+func GetWarnLead(appState *domain.AppState, chatId domain.ChatID) time.Duration {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	return appState.ReadSettings(chatId).WarnLead()
+}
+
+// SetLiveStatus sets whether the chat wants a status message that is kept up
+// to date while each sprint (or rest) goes on.
+func SetLiveStatus(appState *domain.AppState, chatId domain.ChatID, enabled bool) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	chatSettings := appState.ReadSettings(chatId)
+
+	chatSettings.LiveStatus = enabled
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::SetLiveStatus] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+func IsLiveStatusEnabled(appState *domain.AppState, chatId domain.ChatID) bool {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	return appState.ReadSettings(chatId).LiveStatus
+}
+
+// GetGoalProgress returns how the chat is doing with its daily goal.
+func GetGoalProgress(appState *domain.AppState, chatId domain.ChatID) (domain.GoalProgress, error) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	settings := appState.ReadSettings(chatId)
+	now := appState.GetClock().Now().In(settings.Location())
+
+	var days []domain.DailyProgress
+	if appState.PersistenceManager == nil {
+		// The counters are updated by the session timers as well.
+		appState.UsersSettingsLock.RLock()
+		days = append(days, settings.DailyProgress...)
+		appState.UsersSettingsLock.RUnlock()
+	} else {
+		since := domain.DayKey(now.AddDate(0, 0, -domain.MaxStreakDays))
+
+		var err error
+		days, err = appState.PersistenceManager.GetDailyProgress(chatId, since)
+		if err != nil {
+			return domain.GoalProgress{}, err
+		}
+	}
+
+	return domain.ComputeGoalProgress(days, now, settings.DailyGoal), nil
+}
+
+// AddCompletedPomodoro counts a completed pomodoro in the daily counter of
+// the chat and returns the updated progress toward the daily goal.
+func AddCompletedPomodoro(appState *domain.AppState, chatId domain.ChatID) (domain.GoalProgress, error) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	settings := appState.ReadSettings(chatId)
+	now := appState.GetClock().Now().In(settings.Location())
+	today := domain.DayKey(now)
+
+	if appState.PersistenceManager == nil {
+		// This runs on the session timer, while the other goroutines may be
+		// reading the settings.
+		appState.UsersSettingsLock.Lock()
+		oldest := domain.DayKey(now.AddDate(0, 0, -domain.MaxStreakDays))
+		days := settings.DailyProgress[:0]
+		found := false
+		for _, day := range settings.DailyProgress {
+			if day.Day < oldest {
+				// Too old to count in any streak.
+				continue
+			}
+			if day.Day == today {
+				day.Pomodoros += 1
+				found = true
+			}
+			days = append(days, day)
+		}
+		if !found {
+			days = append(days, domain.DailyProgress{Day: today, Pomodoros: 1})
+		}
+		settings.DailyProgress = days
+		appState.UsersSettingsLock.Unlock()
+	} else {
+		err := appState.PersistenceManager.IncrementDailyPomodoros(chatId, today)
+		if err != nil {
+			return domain.GoalProgress{}, err
+		}
+	}
+
+	progress, err := GetGoalProgress(appState, chatId)
+	progress.JustReached = progress.HasGoal() && progress.Today == progress.Goal
+	return progress, err
+}
+
 func PrepareForShutdown(appState *domain.AppState, callback func()) {
 	if appState.PersistenceManager != nil {
 		// We wait for all DB operations to complete
@@ -352,3 +847,63 @@ func PrepareForShutdown(appState *domain.AppState, callback func()) {
 	}
 	callback()
 }
+
+// GetLocalizer returns the localizer of the language the bot speaks in the
+// chat.
+func GetLocalizer(appState *domain.AppState, chatId domain.ChatID) *i18n.Localizer {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	return appState.Messages.Localizer(appState.ReadSettings(chatId).ChatLanguage())
+}
+
+// GetLanguage returns the language set for the chat with /language, empty
+// if the chat follows the one of the Telegram app of the users.
+func GetLanguage(appState *domain.AppState, chatId domain.ChatID) string {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	return appState.ReadSettings(chatId).Language
+}
+
+// SetLanguage sets the language the bot speaks in the chat (empty to follow
+// the one of the Telegram app of the users).
+func SetLanguage(appState *domain.AppState, chatId domain.ChatID, language string) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	chatSettings := appState.ReadSettings(chatId)
+
+	chatSettings.Language = language
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::SetLanguage] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
+
+// DetectLanguage records the language of the Telegram app of the user who
+// wrote in the chat (as sent by Telegram, e.g. "it-IT"), if the bot speaks
+// it. In groups, only the first language detected is kept.
+func DetectLanguage(appState *domain.AppState, chatId domain.ChatID, languageCode string) {
+	defaultUserSettingsIfNeeded(appState, chatId)
+
+	language, ok := appState.Messages.Match(languageCode)
+	if !ok {
+		return
+	}
+
+	chatSettings := appState.ReadSettings(chatId)
+	if chatSettings.DetectedLanguage == language ||
+		(chatSettings.IsGroup && chatSettings.DetectedLanguage != "") {
+		return
+	}
+
+	chatSettings.DetectedLanguage = language
+
+	if appState.PersistenceManager != nil {
+		err := appState.PersistenceManager.StoreChatSettings(chatId, chatSettings)
+		if err != nil {
+			log.Printf("[DataModel::DetectLanguage] error in storing. (%v)\n", err.Error())
+		}
+	}
+}
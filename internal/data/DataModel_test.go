@@ -0,0 +1,81 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package data
+
+import (
+	"GoforPomodoro/internal/domain"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddCompletedPomodoro(t *testing.T) {
+	clock := domain.NewFakeClock(time.Date(2022, time.October, 17, 9, 0, 0, 0, time.UTC))
+	appState := &domain.AppState{
+		Clock:         clock,
+		UsersSettings: make(map[domain.ChatID]*domain.Settings),
+	}
+	SetDailyGoal(appState, 1, 2)
+	appState.ReadSettings(1).DailyProgress = []domain.DailyProgress{
+		{Day: "2021-01-01", Pomodoros: 4},
+		{Day: "2022-10-16", Pomodoros: 2},
+	}
+
+	// The counters are updated by the session timers of different chats.
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := AddCompletedPomodoro(appState, 1); err != nil {
+				t.Errorf("AddCompletedPomodoro returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	progress, err := GetGoalProgress(appState, 1)
+	if err != nil {
+		t.Fatalf("GetGoalProgress returned error: %v", err)
+	}
+	if progress.Today != 2 || progress.Streak != 2 {
+		t.Fatalf("expected 2 pomodoros today and a streak of 2 days, got %+v", progress)
+	}
+
+	expected := []domain.DailyProgress{{Day: "2022-10-16", Pomodoros: 2}, {Day: "2022-10-17", Pomodoros: 2}}
+	days := appState.ReadSettings(1).DailyProgress
+	if len(days) != len(expected) || days[0] != expected[0] || days[1] != expected[1] {
+		t.Fatalf("expected the days %v, got %v", expected, days)
+	}
+}
+
+func TestFlowIsAChatSetting(t *testing.T) {
+	appState := &domain.AppState{
+		UsersSettings: make(map[domain.ChatID]*domain.Settings),
+	}
+	SetFlow(appState, 1, true)
+
+	// A new session pattern (or preset) does not turn flow mode off.
+	UpdateDefaultUserSession(appState, 1, 1, domain.DefaultSession())
+	if !GetUserSessionFromSettings(appState, 1, 1).Flow {
+		t.Fatalf("the sessions of the chat should be in flow mode")
+	}
+
+	SetFlow(appState, 1, false)
+	if GetUserSessionFromSettings(appState, 1, 1).Flow {
+		t.Fatalf("the sessions of the chat should not be in flow mode")
+	}
+}
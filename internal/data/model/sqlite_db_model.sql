@@ -21,28 +21,133 @@ CREATE TABLE IF NOT EXISTS chat_settings(
     default_sprint_duration_set   INTEGER,
     default_pomodoro_duration_set INTEGER,
     default_rest_duration_set     INTEGER,
+    default_long_rest_duration_set INTEGER,
+    default_long_rest_interval    INTEGER,
+    default_plan                  TEXT, -- steps as 'pomodoro/rest' seconds, space separated
 
     running_sprint_duration_set   INTEGER,
     running_pomodoro_duration_set INTEGER,
     running_rest_duration_set     INTEGER,
+    running_long_rest_duration_set INTEGER,
+    running_long_rest_interval    INTEGER,
+    running_plan                  TEXT,
+    running_flow                  INTEGER, -- bool
 
     running_sprint_duration       INTEGER,
     running_pomodoro_duration     INTEGER,
     running_rest_duration         INTEGER,
+    running_plan_step             INTEGER,
 
     running_end_next_sprint_ts    TIMESTAMP,
     running_end_next_rest_ts      TIMESTAMP,
+    running_phase_start_ts        TIMESTAMP,
+    running_phase_extension       INTEGER, -- seconds
+    running_phase_warned          INTEGER, -- bool
+    running_focus_duration        INTEGER, -- seconds
+    running_interruptions_internal INTEGER,
+    running_interruptions_external INTEGER,
 
-    running_is_cancel             INTEGER, -- bool
-    running_is_paused             INTEGER, -- bool
-    running_is_rest               INTEGER, -- bool
-    running_is_finished           INTEGER, -- bool
+    running_state                 TEXT, -- 'pending', 'sprint', 'rest', 'paused_sprint', ...
 
     autorun                       INTEGER, -- bool
     is_group                      INTEGER, -- bool
     subscribers                   TEXT, -- we use this to store de-normalized arrays (encoded)
+    timezone                      TEXT, -- IANA name, e.g. 'Europe/Rome'
+    daily_goal                    INTEGER, -- pomodoros per day, 0 if unset
+    warn_lead                     INTEGER, -- minutes, 0 if unset
+    live_status                   INTEGER, -- bool
+    manual_advance                INTEGER, -- bool
+    ready_timeout                 INTEGER, -- minutes, 0 if unset
+    flow                          INTEGER, -- bool
+    limits_override               TEXT, -- JSON-encoded SessionLimits, '' if none
+    language                      TEXT, -- chosen with /language, '' if unset
+    detected_language             TEXT, -- of the Telegram app of the users
 
     active                        INTEGER -- bool
 );
 
 CREATE INDEX ex1 ON chat_settings(active) WHERE active = 1;
+
+
+DROP TABLE IF EXISTS pomodoro_history;
+
+-- Append-only history of the sprints and rests that came to an end.
+CREATE TABLE IF NOT EXISTS pomodoro_history(
+    id                            INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id                       INTEGER NOT NULL,
+
+    kind                          TEXT NOT NULL, -- 'sprint' or 'rest'
+
+    started_at                    INTEGER, -- unix time
+    ended_at                      INTEGER, -- unix time
+
+    planned_duration              INTEGER, -- seconds
+    actual_duration               INTEGER, -- seconds
+
+    outcome                       TEXT NOT NULL -- 'completed', 'canceled', 'skipped', 'voided'
+);
+
+CREATE INDEX ex2 ON pomodoro_history(chat_id, ended_at);
+
+
+DROP TABLE IF EXISTS chat_presets;
+
+-- Sessions saved by the chats under a name.
+CREATE TABLE IF NOT EXISTS chat_presets(
+    chat_id                       INTEGER NOT NULL,
+    name                          TEXT NOT NULL,
+
+    pattern                       TEXT NOT NULL, -- e.g. '50for3rest10'
+    is_default                    INTEGER NOT NULL DEFAULT 0, -- bool
+
+    PRIMARY KEY (chat_id, name)
+);
+
+
+DROP TABLE IF EXISTS chat_schedules;
+
+-- Sessions that start by themselves, once or on recurring days.
+CREATE TABLE IF NOT EXISTS chat_schedules(
+    id                            INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id                       INTEGER NOT NULL,
+    sender_id                     INTEGER NOT NULL,
+
+    recurrence                    TEXT NOT NULL, -- 'once', 'daily', 'weekday', 'weekend', 'monday', ...
+    hour                          INTEGER NOT NULL,
+    minute                        INTEGER NOT NULL,
+    at                            INTEGER, -- unix time (only for 'once')
+
+    pattern                       TEXT NOT NULL -- e.g. '25for4', '' for the session of the chat
+);
+
+CREATE INDEX ex3 ON chat_schedules(chat_id);
+
+
+DROP TABLE IF EXISTS daily_progress;
+
+-- Pomodoros completed by the chats each day.
+CREATE TABLE IF NOT EXISTS daily_progress(
+    chat_id                       INTEGER NOT NULL,
+    day                           TEXT NOT NULL, -- local date of the chat, 'YYYY-MM-DD'
+
+    pomodoros                     INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (chat_id, day)
+);
+
+
+DROP TABLE IF EXISTS interruptions;
+
+-- Append-only log of the interruptions of the sprints.
+CREATE TABLE IF NOT EXISTS interruptions(
+    id                            INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id                       INTEGER NOT NULL,
+
+    kind                          TEXT NOT NULL, -- 'internal' or 'external'
+    note                          TEXT,
+
+    at                            INTEGER, -- unix time
+    sprint_started_at             INTEGER  -- unix time, as started_at in pomodoro_history
+);
+
+CREATE INDEX ex4 ON interruptions(chat_id, at);
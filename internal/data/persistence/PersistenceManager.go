@@ -18,6 +18,7 @@ package persistence
 import (
 	"GoforPomodoro/internal/domain"
 	"GoforPomodoro/internal/utils"
+	"time"
 )
 
 // Manager interface for types that want to manage persistence.
@@ -32,6 +33,17 @@ import (
 // Then GetActiveChatSettings is defined for a (possibly efficient) retrieval
 // of the chats that have/had a session running.
 //
+// The history of the sprints and rests is kept apart from the settings, in an
+// append-only fashion: AddHistoryRecord stores a new record and GetFocusStats
+// aggregates the records of a chat. So are the interruptions logged during
+// the sprints (AddInterruption), which GetFocusStats counts as well.
+//
+// The presets of a chat (GetPresets, StorePreset, DeletePreset) are kept apart
+// as well, one record per preset, and so are the scheduled starts of the
+// sessions (GetSchedules, GetAllSchedules, AddSchedule, DeleteSchedule) and
+// the daily counters of the completed pomodoros (IncrementDailyPomodoros,
+// GetDailyProgress).
+//
 // Since the store is as of now thought to be key-value based, the user of this
 // interface is not expected to perform complex queries, but just the minimum
 // that is needed for correctly running the bot.
@@ -44,6 +56,46 @@ type Manager interface {
 
 	GetActiveChatSettings() ([]utils.Pair[domain.ChatID, *domain.Settings], error)
 
+	AddHistoryRecord(record domain.HistoryRecord) error
+
+	// GetFocusStats aggregates the sprints of the provided chat ended after
+	// `since`, and the interruptions logged after `since`.
+	GetFocusStats(id domain.ChatID, since time.Time) (domain.FocusStats, error)
+
+	AddInterruption(interruption domain.Interruption) error
+
+	// GetPresets get the presets of the provided chat, sorted by name.
+	GetPresets(id domain.ChatID) ([]domain.Preset, error)
+
+	// StorePreset inserts or replaces the preset with the same name. If the
+	// preset is the default one, the other presets of the chat stop being so.
+	StorePreset(id domain.ChatID, preset domain.Preset) error
+
+	// DeletePreset returns domain.PresetNotFound if there is no such preset.
+	DeletePreset(id domain.ChatID, name string) error
+
+	// GetSchedules get the scheduled starts of the provided chat, sorted by ID.
+	GetSchedules(id domain.ChatID) ([]domain.SessionSchedule, error)
+
+	// GetAllSchedules get the scheduled starts of all the chats, to arm them
+	// again after a restart.
+	GetAllSchedules() ([]domain.SessionSchedule, error)
+
+	// AddSchedule stores a new scheduled start and returns its ID.
+	AddSchedule(schedule domain.SessionSchedule) (int64, error)
+
+	// DeleteSchedule returns domain.ScheduleNotFound if the chat has no such
+	// scheduled start.
+	DeleteSchedule(id domain.ChatID, scheduleId int64) error
+
+	// IncrementDailyPomodoros adds a completed pomodoro to the counter of the
+	// provided day (a local date of the chat, see domain.DayLayout).
+	IncrementDailyPomodoros(id domain.ChatID, day string) error
+
+	// GetDailyProgress get the daily counters of the provided chat from the
+	// day `since` on, most recent first.
+	GetDailyProgress(id domain.ChatID, since string) ([]domain.DailyProgress, error)
+
 	LockDB()
 	UnlockDB()
 }
@@ -43,6 +43,62 @@ type SqliteManager struct {
 	// deleteChatSettingsItem 1 parameter (chat_id)
 	deleteChatSettingsItem *sql.Stmt
 
+	// insertHistoryItem all parameters (chat_id, kind, ...)
+	insertHistoryItem *sql.Stmt
+
+	// getFocusStats 2 parameters (chat_id, ended_at)
+	getFocusStats *sql.Stmt
+
+	// deleteHistoryItems 1 parameter (chat_id)
+	deleteHistoryItems *sql.Stmt
+
+	// getPresetItems 1 parameter (chat_id)
+	getPresetItems *sql.Stmt
+
+	// upsertPresetItem all parameters (chat_id, name, ...)
+	upsertPresetItem *sql.Stmt
+
+	// clearDefaultPresets 2 parameters (chat_id, name of the preset to keep)
+	clearDefaultPresets *sql.Stmt
+
+	// deletePresetItem 2 parameters (chat_id, name)
+	deletePresetItem *sql.Stmt
+
+	// deletePresetItems 1 parameter (chat_id)
+	deletePresetItems *sql.Stmt
+
+	// getScheduleItems 1 parameter (chat_id)
+	getScheduleItems *sql.Stmt
+
+	getAllScheduleItems *sql.Stmt
+
+	// insertScheduleItem all parameters (chat_id, sender_id, ...)
+	insertScheduleItem *sql.Stmt
+
+	// deleteScheduleItem 2 parameters (chat_id, id)
+	deleteScheduleItem *sql.Stmt
+
+	// deleteScheduleItems 1 parameter (chat_id)
+	deleteScheduleItems *sql.Stmt
+
+	// incrementDailyProgressItem 2 parameters (chat_id, day)
+	incrementDailyProgressItem *sql.Stmt
+
+	// getDailyProgressItems 2 parameters (chat_id, day)
+	getDailyProgressItems *sql.Stmt
+
+	// deleteDailyProgressItems 1 parameter (chat_id)
+	deleteDailyProgressItems *sql.Stmt
+
+	// insertInterruptionItem all parameters (chat_id, kind, ...)
+	insertInterruptionItem *sql.Stmt
+
+	// getInterruptionStats 2 parameters (chat_id, at)
+	getInterruptionStats *sql.Stmt
+
+	// deleteInterruptionItems 1 parameter (chat_id)
+	deleteInterruptionItems *sql.Stmt
+
 	requestChan chan interface{}
 }
 
@@ -91,6 +147,93 @@ type GetActiveChatSettingsResponse struct {
 	err      error
 }
 
+type AddHistoryRecordRequest struct {
+	record       domain.HistoryRecord
+	responseChan chan error
+}
+
+type AddInterruptionRequest struct {
+	interruption domain.Interruption
+	responseChan chan error
+}
+
+type GetFocusStatsRequest struct {
+	id           domain.ChatID
+	since        time.Time
+	responseChan chan GetFocusStatsResponse
+}
+
+type GetFocusStatsResponse struct {
+	stats domain.FocusStats
+	err   error
+}
+
+type GetPresetsRequest struct {
+	id           domain.ChatID
+	responseChan chan GetPresetsResponse
+}
+
+type GetPresetsResponse struct {
+	presets []domain.Preset
+	err     error
+}
+
+type StorePresetRequest struct {
+	id           domain.ChatID
+	preset       domain.Preset
+	responseChan chan error
+}
+
+type DeletePresetRequest struct {
+	id           domain.ChatID
+	name         string
+	responseChan chan error
+}
+
+type GetSchedulesRequest struct {
+	id           domain.ChatID
+	all          bool
+	responseChan chan GetSchedulesResponse
+}
+
+type GetSchedulesResponse struct {
+	schedules []domain.SessionSchedule
+	err       error
+}
+
+type AddScheduleRequest struct {
+	schedule     domain.SessionSchedule
+	responseChan chan AddScheduleResponse
+}
+
+type AddScheduleResponse struct {
+	scheduleId int64
+	err        error
+}
+
+type DeleteScheduleRequest struct {
+	id           domain.ChatID
+	scheduleId   int64
+	responseChan chan error
+}
+
+type IncrementDailyPomodorosRequest struct {
+	id           domain.ChatID
+	day          string
+	responseChan chan error
+}
+
+type GetDailyProgressRequest struct {
+	id           domain.ChatID
+	since        string
+	responseChan chan GetDailyProgressResponse
+}
+
+type GetDailyProgressResponse struct {
+	days []domain.DailyProgress
+	err  error
+}
+
 // Ensure that there is only a single SqliteManager at a time running for the same DB.
 // This channeled approach is designed to avoid locking/unlocking of resources
 // No more than one instance at a time should access to the DB.
@@ -110,6 +253,39 @@ func (m *SqliteManager) run() {
 		case GetActiveChatSettingsRequest:
 			settings, err := m.getActiveChatSettings()
 			r.responseChan <- GetActiveChatSettingsResponse{settings: settings, err: err}
+		case AddHistoryRecordRequest:
+			err := m.addHistoryRecord(r.record)
+			r.responseChan <- err
+		case AddInterruptionRequest:
+			err := m.addInterruption(r.interruption)
+			r.responseChan <- err
+		case GetFocusStatsRequest:
+			stats, err := m.getFocusStatsSince(r.id, r.since)
+			r.responseChan <- GetFocusStatsResponse{stats: stats, err: err}
+		case GetPresetsRequest:
+			presets, err := m.getPresets(r.id)
+			r.responseChan <- GetPresetsResponse{presets: presets, err: err}
+		case StorePresetRequest:
+			err := m.storePreset(r.id, r.preset)
+			r.responseChan <- err
+		case DeletePresetRequest:
+			err := m.deletePreset(r.id, r.name)
+			r.responseChan <- err
+		case GetSchedulesRequest:
+			schedules, err := m.getSchedules(r.id, r.all)
+			r.responseChan <- GetSchedulesResponse{schedules: schedules, err: err}
+		case AddScheduleRequest:
+			scheduleId, err := m.addSchedule(r.schedule)
+			r.responseChan <- AddScheduleResponse{scheduleId: scheduleId, err: err}
+		case DeleteScheduleRequest:
+			err := m.deleteSchedule(r.id, r.scheduleId)
+			r.responseChan <- err
+		case IncrementDailyPomodorosRequest:
+			_, err := m.incrementDailyProgressItem.Exec(r.id, r.day)
+			r.responseChan <- err
+		case GetDailyProgressRequest:
+			days, err := m.getDailyProgress(r.id, r.since)
+			r.responseChan <- GetDailyProgressResponse{days: days, err: err}
 		}
 	}
 }
@@ -160,6 +336,138 @@ func (m *SqliteManager) GetActiveChatSettings() ([]utils.Pair[domain.ChatID, *do
 	return response.settings, response.err
 }
 
+func (m *SqliteManager) AddHistoryRecord(record domain.HistoryRecord) error {
+	responseChan := make(chan error)
+	request := AddHistoryRecordRequest{
+		record:       record,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	return <-responseChan
+}
+
+func (m *SqliteManager) AddInterruption(interruption domain.Interruption) error {
+	responseChan := make(chan error)
+	request := AddInterruptionRequest{
+		interruption: interruption,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	return <-responseChan
+}
+
+func (m *SqliteManager) GetFocusStats(id domain.ChatID, since time.Time) (domain.FocusStats, error) {
+	responseChan := make(chan GetFocusStatsResponse)
+	request := GetFocusStatsRequest{
+		id:           id,
+		since:        since,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	response := <-responseChan
+	return response.stats, response.err
+}
+
+func (m *SqliteManager) GetPresets(id domain.ChatID) ([]domain.Preset, error) {
+	responseChan := make(chan GetPresetsResponse)
+	request := GetPresetsRequest{
+		id:           id,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	response := <-responseChan
+	return response.presets, response.err
+}
+
+func (m *SqliteManager) StorePreset(id domain.ChatID, preset domain.Preset) error {
+	responseChan := make(chan error)
+	request := StorePresetRequest{
+		id:           id,
+		preset:       preset,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	return <-responseChan
+}
+
+func (m *SqliteManager) DeletePreset(id domain.ChatID, name string) error {
+	responseChan := make(chan error)
+	request := DeletePresetRequest{
+		id:           id,
+		name:         name,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	return <-responseChan
+}
+
+func (m *SqliteManager) GetSchedules(id domain.ChatID) ([]domain.SessionSchedule, error) {
+	responseChan := make(chan GetSchedulesResponse)
+	request := GetSchedulesRequest{
+		id:           id,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	response := <-responseChan
+	return response.schedules, response.err
+}
+
+func (m *SqliteManager) GetAllSchedules() ([]domain.SessionSchedule, error) {
+	responseChan := make(chan GetSchedulesResponse)
+	request := GetSchedulesRequest{
+		all:          true,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	response := <-responseChan
+	return response.schedules, response.err
+}
+
+func (m *SqliteManager) AddSchedule(schedule domain.SessionSchedule) (int64, error) {
+	responseChan := make(chan AddScheduleResponse)
+	request := AddScheduleRequest{
+		schedule:     schedule,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	response := <-responseChan
+	return response.scheduleId, response.err
+}
+
+func (m *SqliteManager) DeleteSchedule(id domain.ChatID, scheduleId int64) error {
+	responseChan := make(chan error)
+	request := DeleteScheduleRequest{
+		id:           id,
+		scheduleId:   scheduleId,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	return <-responseChan
+}
+
+func (m *SqliteManager) IncrementDailyPomodoros(id domain.ChatID, day string) error {
+	responseChan := make(chan error)
+	request := IncrementDailyPomodorosRequest{
+		id:           id,
+		day:          day,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	return <-responseChan
+}
+
+func (m *SqliteManager) GetDailyProgress(id domain.ChatID, since string) ([]domain.DailyProgress, error) {
+	responseChan := make(chan GetDailyProgressResponse)
+	request := GetDailyProgressRequest{
+		id:           id,
+		since:        since,
+		responseChan: responseChan,
+	}
+	m.requestChan <- request
+	response := <-responseChan
+	return response.days, response.err
+}
+
 func (m *SqliteManager) OpenDatabase(dataSourceName string) error {
 	if _, err := os.Stat(dataSourceName); err != nil {
 		// file does not exist or is not available.
@@ -172,6 +480,10 @@ func (m *SqliteManager) OpenDatabase(dataSourceName string) error {
 		log.Println("[SqliteManager] ERROR AT OPENING DATABASE")
 	} else {
 		m.db = db
+		if err = m.upgradeSchema(); err != nil {
+			log.Printf("[SqliteManager] ERROR AT UPGRADING DATABASE (%s)\n", err.Error())
+			return err
+		}
 		m.InitializePreparedStatements()
 		m.requestChan = make(chan interface{})
 		go m.run()
@@ -203,42 +515,84 @@ func (m *SqliteManager) InitializePreparedStatements() {
 			default_sprint_duration_set,   
 			default_pomodoro_duration_set, 
 			default_rest_duration_set,     
+			default_long_rest_duration_set,
+			default_long_rest_interval,    
+			default_plan,                  
 			running_sprint_duration_set,   
 			running_pomodoro_duration_set, 
 			running_rest_duration_set,     
+			running_long_rest_duration_set,
+			running_long_rest_interval,    
+			running_plan,                  
+			running_flow,                  
 			running_sprint_duration,       
 			running_pomodoro_duration,     
 			running_rest_duration,         
+			running_plan_step,             
 			running_end_next_sprint_ts,    
 			running_end_next_rest_ts,      
-			running_is_cancel,             
-			running_is_paused,             
-			running_is_rest,               
-			running_is_finished,           
+			running_phase_start_ts,        
+			running_phase_extension,       
+			running_phase_warned,          
+			running_focus_duration,        
+			running_interruptions_internal,
+			running_interruptions_external,
+			running_state,                 
 			autorun,                       
 			is_group,                      
 			subscribers,                   
+			timezone,                      
+			daily_goal,                    
+			warn_lead,                     
+			live_status,                   
+			manual_advance,                
+			ready_timeout,                 
+			flow,                          
+			limits_override,               
+			language,                      
+			detected_language,             
 			active)
-			VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
+			VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
 			ON CONFLICT (chat_id) DO UPDATE SET
 			default_sprint_duration_set = ?,   
 			default_pomodoro_duration_set = ?, 
 			default_rest_duration_set = ?,     
+			default_long_rest_duration_set = ?,
+			default_long_rest_interval = ?,    
+			default_plan = ?,                  
 			running_sprint_duration_set = ?,   
 			running_pomodoro_duration_set = ?, 
 			running_rest_duration_set = ?,     
+			running_long_rest_duration_set = ?,
+			running_long_rest_interval = ?,    
+			running_plan = ?,                  
+			running_flow = ?,                  
 			running_sprint_duration = ?,       
 			running_pomodoro_duration = ?,     
 			running_rest_duration = ?,         
+			running_plan_step = ?,             
 			running_end_next_sprint_ts = ?,    
 			running_end_next_rest_ts = ?,      
-			running_is_cancel = ?,             
-			running_is_paused = ?,             
-			running_is_rest = ?,               
-			running_is_finished = ?,           
+			running_phase_start_ts = ?,        
+			running_phase_extension = ?,       
+			running_phase_warned = ?,          
+			running_focus_duration = ?,        
+			running_interruptions_internal = ?,
+			running_interruptions_external = ?,
+			running_state = ?,                 
 			autorun = ?,                       
 			is_group = ?,                      
 			subscribers = ?,                   
+			timezone = ?,                      
+			daily_goal = ?,                    
+			warn_lead = ?,                     
+			live_status = ?,                   
+			manual_advance = ?,                
+			ready_timeout = ?,                 
+			flow = ?,                          
+			limits_override = ?,               
+			language = ?,                      
+			detected_language = ?,             
 			active = ?
 		WHERE chat_id = ?
 	`)
@@ -282,6 +636,201 @@ func (m *SqliteManager) InitializePreparedStatements() {
 		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (DELETE)! (%s)\n", err.Error())
 		panic(err)
 	}
+
+	m.insertHistoryItem, err = m.db.Prepare(`
+		INSERT INTO pomodoro_history
+			(chat_id,
+			kind,
+			started_at,
+			ended_at,
+			planned_duration,
+			actual_duration,
+			outcome)
+			VALUES (?,?,?,?,?,?,?)`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (INSERT HISTORY)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.getFocusStats, err = m.db.Prepare(`
+		SELECT COUNT(CASE WHEN outcome = 'completed' THEN 1 END),
+		       COALESCE(SUM(actual_duration), 0)
+		FROM pomodoro_history
+		WHERE chat_id = ? AND kind = 'sprint' AND ended_at >= ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (SELECT HISTORY)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.deleteHistoryItems, err = m.db.Prepare(`
+		DELETE FROM pomodoro_history
+		WHERE chat_id = ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (DELETE HISTORY)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.getPresetItems, err = m.db.Prepare(`
+		SELECT name, pattern, is_default
+		FROM chat_presets
+		WHERE chat_id = ?
+		ORDER BY name`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (SELECT PRESETS)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.upsertPresetItem, err = m.db.Prepare(`
+		INSERT INTO chat_presets
+			(chat_id,
+			name,
+			pattern,
+			is_default)
+			VALUES (?,?,?,?)
+			ON CONFLICT (chat_id, name) DO UPDATE SET
+			pattern = ?,
+			is_default = ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (INSERT PRESET)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.clearDefaultPresets, err = m.db.Prepare(`
+		UPDATE chat_presets SET
+			is_default = false
+		WHERE chat_id = ? AND name <> ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (UPDATE PRESETS)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.deletePresetItem, err = m.db.Prepare(`
+		DELETE FROM chat_presets
+		WHERE chat_id = ? AND name = ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (DELETE PRESET)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.deletePresetItems, err = m.db.Prepare(`
+		DELETE FROM chat_presets
+		WHERE chat_id = ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (DELETE PRESETS)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.getScheduleItems, err = m.db.Prepare(`
+		SELECT id, chat_id, sender_id, recurrence, hour, minute, at, pattern
+		FROM chat_schedules
+		WHERE chat_id = ?
+		ORDER BY id`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (SELECT SCHEDULES)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.getAllScheduleItems, err = m.db.Prepare(`
+		SELECT id, chat_id, sender_id, recurrence, hour, minute, at, pattern
+		FROM chat_schedules
+		ORDER BY id`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (SELECT ALL SCHEDULES)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.insertScheduleItem, err = m.db.Prepare(`
+		INSERT INTO chat_schedules
+			(chat_id,
+			sender_id,
+			recurrence,
+			hour,
+			minute,
+			at,
+			pattern)
+			VALUES (?,?,?,?,?,?,?)`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (INSERT SCHEDULE)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.deleteScheduleItem, err = m.db.Prepare(`
+		DELETE FROM chat_schedules
+		WHERE chat_id = ? AND id = ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (DELETE SCHEDULE)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.deleteScheduleItems, err = m.db.Prepare(`
+		DELETE FROM chat_schedules
+		WHERE chat_id = ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (DELETE SCHEDULES)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.incrementDailyProgressItem, err = m.db.Prepare(`
+		INSERT INTO daily_progress
+			(chat_id,
+			day,
+			pomodoros)
+			VALUES (?,?,1)
+			ON CONFLICT (chat_id, day) DO UPDATE SET
+			pomodoros = pomodoros + 1`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (INSERT DAILY PROGRESS)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.getDailyProgressItems, err = m.db.Prepare(`
+		SELECT day, pomodoros
+		FROM daily_progress
+		WHERE chat_id = ? AND day >= ?
+		ORDER BY day DESC`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (SELECT DAILY PROGRESS)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.deleteDailyProgressItems, err = m.db.Prepare(`
+		DELETE FROM daily_progress
+		WHERE chat_id = ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (DELETE DAILY PROGRESS)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.insertInterruptionItem, err = m.db.Prepare(`
+		INSERT INTO interruptions
+			(chat_id,
+			kind,
+			note,
+			at,
+			sprint_started_at)
+			VALUES (?,?,?,?,?)`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (INSERT INTERRUPTION)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.getInterruptionStats, err = m.db.Prepare(`
+		SELECT COUNT(CASE WHEN kind = 'internal' THEN 1 END),
+		       COUNT(CASE WHEN kind = 'external' THEN 1 END)
+		FROM interruptions
+		WHERE chat_id = ? AND at >= ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (SELECT INTERRUPTIONS)! (%s)\n", err.Error())
+		panic(err)
+	}
+
+	m.deleteInterruptionItems, err = m.db.Prepare(`
+		DELETE FROM interruptions
+		WHERE chat_id = ?`)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR IN PREPARING STATEMENTS (DELETE INTERRUPTIONS)! (%s)\n", err.Error())
+		panic(err)
+	}
 }
 
 type Scannable interface {
@@ -308,6 +857,20 @@ func (m *SqliteManager) getChatSettings(chatId *domain.ChatID, row Scannable) (*
 
 	var endNextSprintTimestamp *time.Time
 	var endNextRestTimestamp *time.Time
+	var phaseStartTimestamp *time.Time
+	var runningStateKey string
+	var timezone string
+	var dailyGoal int
+	var warnLead int
+	var liveStatus bool
+	var manualAdvance bool
+	var readyTimeout int
+	var flow bool
+	var limitsText string
+	var language string
+	var detectedLanguage string
+	var defaultPlanText string
+	var runningPlanText string
 
 	var _chatId domain.ChatID
 	scanErr := row.Scan(
@@ -315,25 +878,46 @@ func (m *SqliteManager) getChatSettings(chatId *domain.ChatID, row Scannable) (*
 		&defaultS.SprintDurationSet,
 		&defaultS.PomodoroDurationSet,
 		&defaultS.RestDurationSet,
+		&defaultS.LongRestDurationSet,
+		&defaultS.LongRestInterval,
+		&defaultPlanText,
 
 		&runningS.SprintDurationSet,
 		&runningS.PomodoroDurationSet,
 		&runningS.RestDurationSet,
+		&runningS.LongRestDurationSet,
+		&runningS.LongRestInterval,
+		&runningPlanText,
+		&runningS.Flow,
 
 		&runningS.SprintDuration,
 		&runningS.PomodoroDuration,
 		&runningS.RestDuration,
+		&runningS.PlanStep,
 
 		&endNextSprintTimestamp,
 		&endNextRestTimestamp,
-
-		&runningS.IsCancel,
-		&runningS.IsPaused,
-		&runningS.IsRest,
-		&runningS.IsFinished,
+		&phaseStartTimestamp,
+		&runningS.PhaseExtension,
+		&runningS.PhaseWarned,
+		&runningS.FocusDuration,
+		&runningS.Interruptions.Internal,
+		&runningS.Interruptions.External,
+
+		&runningStateKey,
 		&autorun,
 		&isGroup,
 		&subscribersText,
+		&timezone,
+		&dailyGoal,
+		&warnLead,
+		&liveStatus,
+		&manualAdvance,
+		&readyTimeout,
+		&flow,
+		&limitsText,
+		&language,
+		&detectedLanguage,
 		&active,
 	)
 
@@ -359,6 +943,29 @@ func (m *SqliteManager) getChatSettings(chatId *domain.ChatID, row Scannable) (*
 		runningS.EndNextRestTimestamp = *endNextRestTimestamp
 	}
 
+	if phaseStartTimestamp != nil {
+		runningS.PhaseStartTimestamp = *phaseStartTimestamp
+	}
+
+	runningState, stateErr := domain.ParseSessionState(runningStateKey)
+	if stateErr != nil {
+		log.Printf("[SqliteManager] ERROR AT DECODING SESSION STATE (%v)\n", stateErr.Error())
+		return nil, stateErr
+	}
+	runningS.State = runningState
+
+	var planErr error
+	defaultS.Plan, planErr = domain.DecodeSessionPlan(defaultPlanText)
+	if planErr != nil {
+		log.Printf("[SqliteManager] ERROR AT DECODING SESSION PLAN (%v)\n", planErr.Error())
+		return nil, planErr
+	}
+	runningS.Plan, planErr = domain.DecodeSessionPlan(runningPlanText)
+	if planErr != nil {
+		log.Printf("[SqliteManager] ERROR AT DECODING SESSION PLAN (%v)\n", planErr.Error())
+		return nil, planErr
+	}
+
 	if subscribersText != "" {
 		jsonErr := json.Unmarshal([]byte(subscribersText), &subscribers)
 		if jsonErr != nil {
@@ -369,12 +976,35 @@ func (m *SqliteManager) getChatSettings(chatId *domain.ChatID, row Scannable) (*
 		}
 	}
 
+	var limitsOverride domain.SessionLimits
+	if limitsText != "" {
+		jsonErr := json.Unmarshal([]byte(limitsText), &limitsOverride)
+		if jsonErr != nil {
+			log.Printf("[SqliteManager] ERROR AT DECODING JSON FROM (%v)\n", limitsText)
+
+			return nil, jsonErr
+		}
+	}
+
 	settings := &domain.Settings{
-		SessionDefault: defaultS,
-		SessionRunning: runningS.ToSession(),
-		Autorun:        autorun,
-		IsGroup:        isGroup,
-		Subscribers:    subscribers,
+		SessionDefault:  defaultS,
+		SessionRunning:  runningS.ToSession(),
+		Autorun:         autorun,
+		IsGroup:         isGroup,
+		Subscribers:     subscribers,
+		Timezone:        timezone,
+		DailyGoal:       dailyGoal,
+		WarnLeadMinutes: warnLead,
+		LiveStatus:      liveStatus,
+
+		ManualAdvance:       manualAdvance,
+		ReadyTimeoutMinutes: readyTimeout,
+		Flow:                flow,
+
+		LimitsOverride: limitsOverride,
+
+		Language:         language,
+		DetectedLanguage: detectedLanguage,
 	}
 	return settings, nil
 }
@@ -398,69 +1028,139 @@ func (m *SqliteManager) storeChatSettings(chatId domain.ChatID, settings *domain
 	defaultSprintDurationSet := settings.SessionDefault.SprintDurationSet
 	defaultPomodoroDurationSet := settings.SessionDefault.PomodoroDurationSet
 	defaultRestDurationSet := settings.SessionDefault.RestDurationSet
+	defaultLongRestDurationSet := settings.SessionDefault.LongRestDurationSet
+	defaultLongRestInterval := settings.SessionDefault.LongRestInterval
+	defaultPlan := settings.SessionDefault.Plan.Encode()
 
 	runningSprintDurationSet := sessionRunning.GetSprintDurationSet()
 	runningPomodoroDurationSet := sessionRunning.GetPomodoroDurationSet()
 	runningRestDurationSet := sessionRunning.GetRestDurationSet()
+	runningLongRestDurationSet := sessionRunning.GetLongRestDurationSet()
+	runningLongRestInterval := sessionRunning.GetLongRestInterval()
+	runningPlan := sessionRunning.GetPlan().Encode()
+	runningFlow := sessionRunning.IsFlow()
 
 	runningSprintDuration := sessionRunning.GetSprintDuration()
 	runningPomodoroDuration := sessionRunning.GetPomodoroDuration()
 	runningRestDuration := sessionRunning.GetRestDuration()
-
-	endNextSprintTs := sessionRunning.EndNextSprintTimestamp()
-	endNextRestTs := sessionRunning.EndNextRestTimestamp()
-
-	runningIsCancel := sessionRunning.IsCanceled()
-	runningIsPaused := sessionRunning.IsPaused()
-	runningIsRest := sessionRunning.IsRest()
-	runningIsFinished := sessionRunning.IsFinished()
+	runningPlanStep := sessionRunning.GetPlanStep()
+
+	// Timestamps are stored in UTC, independently of the timezone of the
+	// server and of the chat.
+	endNextSprintTs := toUTC(sessionRunning.EndNextSprintTimestamp())
+	endNextRestTs := toUTC(sessionRunning.EndNextRestTimestamp())
+	phaseStartTs := toUTC(sessionRunning.PhaseStartTimestamp())
+	phaseExtension := int64(sessionRunning.GetPhaseExtension().Seconds())
+	phaseWarned := sessionRunning.IsPhaseWarned()
+	focusDuration := sessionRunning.GetFocusDuration()
+	interruptions := sessionRunning.GetInterruptions()
+
+	runningState := sessionRunning.State().Key()
 	autorun := settings.Autorun
 	isGroup := settings.IsGroup
+	timezone := settings.Timezone
+	dailyGoal := settings.DailyGoal
+	warnLead := settings.WarnLeadMinutes
+	liveStatus := settings.LiveStatus
+	manualAdvance := settings.ManualAdvance
+	readyTimeout := settings.ReadyTimeoutMinutes
+	flow := settings.Flow
+	limitsOverride := ""
+	if !settings.LimitsOverride.IsZero() {
+		limitsJson, errM := json.Marshal(settings.LimitsOverride)
+		if errM != nil {
+			log.Printf("[SqliteManager] ERROR AT ENCODING JSON FROM (%v)\n", settings.LimitsOverride)
+		} else {
+			limitsOverride = string(limitsJson)
+		}
+	}
 	subscribers, errM := json.Marshal(settings.Subscribers)
 	if errM != nil {
 		subscribers = nil
 		log.Printf("[SqliteManager] ERROR AT ENCODING JSON FROM (%v)\n", settings.Subscribers)
 	}
-	active := sessionRunning.State() == "Running"
+	active := sessionRunning.State().IsRunning()
 
 	_, err := m.upsertChatSettingsItem.Exec(chatId,
 		defaultSprintDurationSet,
 		defaultPomodoroDurationSet,
 		defaultRestDurationSet,
+		defaultLongRestDurationSet,
+		defaultLongRestInterval,
+		defaultPlan,
 		runningSprintDurationSet,
 		runningPomodoroDurationSet,
 		runningRestDurationSet,
+		runningLongRestDurationSet,
+		runningLongRestInterval,
+		runningPlan,
+		runningFlow,
 		runningSprintDuration,
 		runningPomodoroDuration,
 		runningRestDuration,
+		runningPlanStep,
 		endNextSprintTs,
 		endNextRestTs,
-		runningIsCancel,
-		runningIsPaused,
-		runningIsRest,
-		runningIsFinished,
+		phaseStartTs,
+		phaseExtension,
+		phaseWarned,
+		focusDuration,
+		interruptions.Internal,
+		interruptions.External,
+		runningState,
 		autorun,
 		isGroup,
 		subscribers,
+		timezone,
+		dailyGoal,
+		warnLead,
+		liveStatus,
+		manualAdvance,
+		readyTimeout,
+		flow,
+		limitsOverride,
+		settings.Language,
+		settings.DetectedLanguage,
 		active,
 		defaultSprintDurationSet,
 		defaultPomodoroDurationSet,
 		defaultRestDurationSet,
+		defaultLongRestDurationSet,
+		defaultLongRestInterval,
+		defaultPlan,
 		runningSprintDurationSet,
 		runningPomodoroDurationSet,
 		runningRestDurationSet,
+		runningLongRestDurationSet,
+		runningLongRestInterval,
+		runningPlan,
+		runningFlow,
 		runningSprintDuration,
 		runningPomodoroDuration,
 		runningRestDuration,
+		runningPlanStep,
 		endNextSprintTs,
 		endNextRestTs,
-		runningIsCancel,
-		runningIsPaused,
-		runningIsRest,
-		runningIsFinished,
+		phaseStartTs,
+		phaseExtension,
+		phaseWarned,
+		focusDuration,
+		interruptions.Internal,
+		interruptions.External,
+		runningState,
 		autorun,
 		isGroup,
 		subscribers,
+		timezone,
+		dailyGoal,
+		warnLead,
+		liveStatus,
+		manualAdvance,
+		readyTimeout,
+		flow,
+		limitsOverride,
+		settings.Language,
+		settings.DetectedLanguage,
 		active,
 		chatId,
 	)
@@ -472,12 +1172,296 @@ func (m *SqliteManager) storeChatSettings(chatId domain.ChatID, settings *domain
 	return err
 }
 
+// toUTC returns the timestamp in UTC (nil if nil).
+func toUTC(timestamp *time.Time) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	return utils.TimePtr(timestamp.UTC())
+}
+
 func (m *SqliteManager) deleteChatSettings(chatId domain.ChatID) error {
 	_, err := m.deleteChatSettingsItem.Exec(chatId)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.deleteHistoryItems.Exec(chatId)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.deletePresetItems.Exec(chatId)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.deleteScheduleItems.Exec(chatId)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.deleteDailyProgressItems.Exec(chatId)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.deleteInterruptionItems.Exec(chatId)
+
+	return err
+}
+
+func (m *SqliteManager) addHistoryRecord(record domain.HistoryRecord) error {
+	if record.ChatID == 0 {
+		return nil
+	}
+
+	_, err := m.insertHistoryItem.Exec(
+		record.ChatID,
+		record.Kind,
+		record.Start.Unix(),
+		record.End.Unix(),
+		record.PlannedDuration,
+		record.ActualDuration,
+		record.Outcome,
+	)
+
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR AT STORING HISTORY RECORD! (%v)\n", err.Error())
+	}
 
 	return err
 }
 
+func (m *SqliteManager) getFocusStatsSince(chatId domain.ChatID, since time.Time) (domain.FocusStats, error) {
+	var stats domain.FocusStats
+
+	var sinceUnix int64 = 0
+	if !since.IsZero() {
+		sinceUnix = since.Unix()
+	}
+
+	err := m.getFocusStats.QueryRow(chatId, sinceUnix).Scan(&stats.Pomodoros, &stats.FocusTime)
+	if err != nil {
+		return stats, err
+	}
+
+	err = m.getInterruptionStats.QueryRow(chatId, sinceUnix).Scan(
+		&stats.Interruptions.Internal,
+		&stats.Interruptions.External,
+	)
+
+	return stats, err
+}
+
+func (m *SqliteManager) addInterruption(interruption domain.Interruption) error {
+	if interruption.ChatID == 0 {
+		return nil
+	}
+
+	var sprintStart *int64
+	if !interruption.SprintStart.IsZero() {
+		sprintStartUnix := interruption.SprintStart.Unix()
+		sprintStart = &sprintStartUnix
+	}
+
+	_, err := m.insertInterruptionItem.Exec(
+		interruption.ChatID,
+		interruption.Kind,
+		interruption.Note,
+		interruption.At.Unix(),
+		sprintStart,
+	)
+
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR AT STORING INTERRUPTION! (%v)\n", err.Error())
+	}
+
+	return err
+}
+
+func (m *SqliteManager) getPresets(chatId domain.ChatID) ([]domain.Preset, error) {
+	rows, err := m.getPresetItems.Query(chatId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Printf("[getPresets] err at Close(): %v\n", err.Error())
+		}
+	}()
+
+	var presets []domain.Preset
+
+	for rows.Next() {
+		var preset domain.Preset
+
+		scanErr := rows.Scan(&preset.Name, &preset.Pattern, &preset.IsDefault)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+
+		presets = append(presets, preset)
+	}
+	return presets, rows.Err()
+}
+
+func (m *SqliteManager) storePreset(chatId domain.ChatID, preset domain.Preset) error {
+	_, err := m.upsertPresetItem.Exec(
+		chatId,
+		preset.Name,
+		preset.Pattern,
+		preset.IsDefault,
+		preset.Pattern,
+		preset.IsDefault,
+	)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR AT STORING PRESET! (%v)\n", err.Error())
+		return err
+	}
+
+	if preset.IsDefault {
+		_, err = m.clearDefaultPresets.Exec(chatId, preset.Name)
+	}
+
+	return err
+}
+
+func (m *SqliteManager) deletePreset(chatId domain.ChatID, name string) error {
+	result, err := m.deletePresetItem.Exec(chatId, name)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.PresetNotFound{Name: name}
+	}
+
+	return nil
+}
+
+func (m *SqliteManager) getSchedules(chatId domain.ChatID, all bool) ([]domain.SessionSchedule, error) {
+	var rows *sql.Rows
+	var err error
+	if all {
+		rows, err = m.getAllScheduleItems.Query()
+	} else {
+		rows, err = m.getScheduleItems.Query(chatId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Printf("[getSchedules] err at Close(): %v\n", err.Error())
+		}
+	}()
+
+	var schedules []domain.SessionSchedule
+
+	for rows.Next() {
+		var schedule domain.SessionSchedule
+		var at int64
+
+		scanErr := rows.Scan(
+			&schedule.ID,
+			&schedule.ChatID,
+			&schedule.SenderID,
+			&schedule.Recurrence,
+			&schedule.Hour,
+			&schedule.Minute,
+			&at,
+			&schedule.Pattern,
+		)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+
+		if at != 0 {
+			schedule.At = time.Unix(at, 0)
+		}
+
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (m *SqliteManager) addSchedule(schedule domain.SessionSchedule) (int64, error) {
+	var at int64 = 0
+	if !schedule.At.IsZero() {
+		at = schedule.At.Unix()
+	}
+
+	result, err := m.insertScheduleItem.Exec(
+		schedule.ChatID,
+		schedule.SenderID,
+		schedule.Recurrence,
+		schedule.Hour,
+		schedule.Minute,
+		at,
+		schedule.Pattern,
+	)
+	if err != nil {
+		log.Printf("[SqliteManager] ERROR AT STORING SCHEDULE! (%v)\n", err.Error())
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (m *SqliteManager) deleteSchedule(chatId domain.ChatID, scheduleId int64) error {
+	result, err := m.deleteScheduleItem.Exec(chatId, scheduleId)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ScheduleNotFound{ID: scheduleId}
+	}
+
+	return nil
+}
+
+func (m *SqliteManager) getDailyProgress(chatId domain.ChatID, since string) ([]domain.DailyProgress, error) {
+	rows, err := m.getDailyProgressItems.Query(chatId, since)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Printf("[getDailyProgress] err at Close(): %v\n", err.Error())
+		}
+	}()
+
+	var days []domain.DailyProgress
+
+	for rows.Next() {
+		var day domain.DailyProgress
+
+		scanErr := rows.Scan(&day.Day, &day.Pomodoros)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
 func (m *SqliteManager) getActiveChatSettings() ([]utils.Pair[domain.ChatID, *domain.Settings], error) {
 	rows, err := m.getActiveChatsSettings.Query()
 	if err != nil {
@@ -0,0 +1,194 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package persistence
+
+import (
+	"GoforPomodoro/internal/data/model"
+	"GoforPomodoro/internal/domain"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestSqliteManager opens a manager on a new database, created from the
+// schema of the bot.
+func newTestSqliteManager(t *testing.T) *SqliteManager {
+	t.Helper()
+
+	return openTestSqliteManager(t, newTestDatabase(t, model.SqliteSchema))
+}
+
+// newTestDatabase creates a database running the given statements.
+func newTestDatabase(t *testing.T, statements string) string {
+	t.Helper()
+
+	dataSourceName := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", dataSourceName)
+	if err != nil {
+		t.Fatalf("cannot create the database: %v", err)
+	}
+	if _, err := db.Exec(statements); err != nil {
+		t.Fatalf("cannot create the tables: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("cannot close the database: %v", err)
+	}
+	return dataSourceName
+}
+
+func openTestSqliteManager(t *testing.T, dataSourceName string) *SqliteManager {
+	t.Helper()
+
+	manager := &SqliteManager{}
+	if err := manager.OpenDatabase(dataSourceName); err != nil {
+		t.Fatalf("OpenDatabase returned error: %v", err)
+	}
+	t.Cleanup(func() { manager.db.Close() })
+	return manager
+}
+
+func TestInterruptionsAreCountedAndReset(t *testing.T) {
+	manager := newTestSqliteManager(t)
+
+	sprintStart := time.Date(2022, time.October, 17, 9, 0, 0, 0, time.UTC)
+	interruptions := []domain.Interruption{
+		{ChatID: 1, Kind: domain.InterruptionInternal, At: sprintStart.Add(5 * time.Minute), SprintStart: sprintStart},
+		{ChatID: 1, Kind: domain.InterruptionExternal, Note: "phone call", At: sprintStart.Add(10 * time.Minute), SprintStart: sprintStart},
+		{ChatID: 1, Kind: domain.InterruptionExternal, At: sprintStart.Add(-24 * time.Hour)},
+		{ChatID: 2, Kind: domain.InterruptionInternal, At: sprintStart.Add(5 * time.Minute), SprintStart: sprintStart},
+	}
+	for _, interruption := range interruptions {
+		if err := manager.AddInterruption(interruption); err != nil {
+			t.Fatalf("AddInterruption returned error: %v", err)
+		}
+	}
+
+	var stored int
+	var note sql.NullString
+	var sprintStartedAt sql.NullInt64
+	err := manager.db.QueryRow(`
+		SELECT COUNT(*), MAX(note), MAX(sprint_started_at)
+		FROM interruptions
+		WHERE chat_id = 1`).Scan(&stored, &note, &sprintStartedAt)
+	if err != nil {
+		t.Fatalf("cannot read the interruptions: %v", err)
+	}
+	if stored != 3 || note.String != "phone call" || sprintStartedAt.Int64 != sprintStart.Unix() {
+		t.Fatalf("unexpected interruptions stored: %d, note %q, sprint start %d", stored, note.String, sprintStartedAt.Int64)
+	}
+
+	stats, err := manager.GetFocusStats(1, sprintStart)
+	if err != nil {
+		t.Fatalf("GetFocusStats returned error: %v", err)
+	}
+	if expected := (domain.InterruptionCount{Internal: 1, External: 1}); stats.Interruptions != expected {
+		t.Fatalf("expected %v interruptions since the sprint, got %v", expected, stats.Interruptions)
+	}
+	stats, err = manager.GetFocusStats(1, time.Time{})
+	if err != nil {
+		t.Fatalf("GetFocusStats returned error: %v", err)
+	}
+	if expected := (domain.InterruptionCount{Internal: 1, External: 2}); stats.Interruptions != expected {
+		t.Fatalf("expected %v interruptions all-time, got %v", expected, stats.Interruptions)
+	}
+
+	if err := manager.DeleteChatSettings(1); err != nil {
+		t.Fatalf("DeleteChatSettings returned error: %v", err)
+	}
+	stats, err = manager.GetFocusStats(1, time.Time{})
+	if err != nil {
+		t.Fatalf("GetFocusStats returned error: %v", err)
+	}
+	if stats.Interruptions.Total() != 0 {
+		t.Fatalf("the interruptions should be deleted on reset, %v are left", stats.Interruptions)
+	}
+	stats, err = manager.GetFocusStats(2, time.Time{})
+	if err != nil {
+		t.Fatalf("GetFocusStats returned error: %v", err)
+	}
+	if stats.Interruptions.Total() != 1 {
+		t.Fatalf("the interruptions of other chats should be kept, %v are left", stats.Interruptions)
+	}
+}
+
+// baselineSchema is the schema of the first releases of the bot, where the
+// state of a session was stored as flags.
+const baselineSchema = `
+CREATE TABLE chat_settings(
+    chat_id                       INTEGER NOT NULL PRIMARY KEY,
+    default_sprint_duration_set   INTEGER,
+    default_pomodoro_duration_set INTEGER,
+    default_rest_duration_set     INTEGER,
+    running_sprint_duration_set   INTEGER,
+    running_pomodoro_duration_set INTEGER,
+    running_rest_duration_set     INTEGER,
+    running_sprint_duration       INTEGER,
+    running_pomodoro_duration     INTEGER,
+    running_rest_duration         INTEGER,
+    running_end_next_sprint_ts    TIMESTAMP,
+    running_end_next_rest_ts      TIMESTAMP,
+    running_is_cancel             INTEGER,
+    running_is_paused             INTEGER,
+    running_is_rest               INTEGER,
+    running_is_finished           INTEGER,
+    autorun                       INTEGER,
+    is_group                      INTEGER,
+    subscribers                   TEXT,
+    active                        INTEGER
+);
+CREATE INDEX ex1 ON chat_settings(active) WHERE active = 1;
+INSERT INTO chat_settings VALUES
+    (1, 4, 25, 5, 4, 25, 5, 2, 10, 5, NULL, NULL, 0, 1, 1, 0, 1, 0, '', 1),
+    (2, 4, 25, 5, 4, 25, 5, 0, 0, 0, NULL, NULL, 0, 0, 0, 1, 0, 0, '', 0);`
+
+func TestOpenDatabaseUpgradesTheSchema(t *testing.T) {
+	manager := openTestSqliteManager(t, newTestDatabase(t, baselineSchema))
+
+	settings, err := manager.GetChatSettings(1)
+	if err != nil {
+		t.Fatalf("GetChatSettings returned error: %v", err)
+	}
+	if state := settings.SessionRunning.State(); state != domain.StatePausedRest {
+		t.Errorf("running session state = %v, want %v", state, domain.StatePausedRest)
+	}
+	if left := settings.SessionRunning.GetSprintDuration(); left != 2 {
+		t.Errorf("sprints left = %d, want 2", left)
+	}
+	if !settings.Autorun {
+		t.Errorf("autorun was lost")
+	}
+
+	settings, err = manager.GetChatSettings(2)
+	if err != nil {
+		t.Fatalf("GetChatSettings returned error: %v", err)
+	}
+	if state := settings.SessionRunning.State(); state != domain.StateFinished {
+		t.Errorf("running session state = %v, want %v", state, domain.StateFinished)
+	}
+
+	active, err := manager.GetActiveChatSettings()
+	if err != nil {
+		t.Fatalf("GetActiveChatSettings returned error: %v", err)
+	}
+	if len(active) != 1 || active[0].First != 1 {
+		t.Errorf("active chats = %v, want only chat 1", active)
+	}
+
+	if err := manager.AddInterruption(domain.Interruption{ChatID: 1, Kind: domain.InterruptionInternal, At: time.Now()}); err != nil {
+		t.Errorf("AddInterruption on the new tables returned error: %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package persistence
+
+import (
+	"GoforPomodoro/internal/data/model"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// schemaTable is a table of model.SqliteSchema.
+type schemaTable struct {
+	name    string
+	sql     string
+	columns []schemaColumn
+	indexes []string
+}
+
+type schemaColumn struct {
+	name  string
+	dtype string
+}
+
+// legacyColumn fills a column of the schema from the columns that replaced it,
+// when a database of an earlier version of the bot is upgraded.
+type legacyColumn struct {
+	table    string
+	column   string
+	requires []string
+	expr     string
+}
+
+var legacyColumns = []legacyColumn{
+	{
+		table:    "chat_settings",
+		column:   "running_state",
+		requires: []string{"running_is_cancel", "running_is_paused", "running_is_rest", "running_is_finished"},
+		expr: `CASE
+			WHEN running_is_cancel = 1 THEN 'canceled'
+			WHEN running_is_finished = 1 THEN 'finished'
+			WHEN running_is_paused = 1 AND running_is_rest = 1 THEN 'paused_rest'
+			WHEN running_is_paused = 1 THEN 'paused_sprint'
+			WHEN running_is_rest = 1 THEN 'rest'
+			ELSE 'sprint'
+		END`,
+	},
+}
+
+// upgradeSchema Bring the database up to date with model.SqliteSchema,
+// keeping its data: the tables that are missing are created, and the ones
+// whose columns changed are rebuilt with the columns of the schema. The
+// columns they did not have are zero (NULL for the timestamps), unless
+// legacyColumns fills them.
+func (m *SqliteManager) upgradeSchema() error {
+	tables, err := schemaTables()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		columns, err := tableColumns(m.db, table.name)
+		if err != nil {
+			return err
+		}
+		if sameColumns(columns, table.columns) {
+			continue
+		}
+
+		log.Printf("[SqliteManager] upgrading table %s\n", table.name)
+		if err := m.upgradeTable(table, columns); err != nil {
+			return fmt.Errorf("cannot upgrade table %s: %w", table.name, err)
+		}
+	}
+	return nil
+}
+
+// upgradeTable Create the table of the schema, moving to it the rows of the
+// existing table with the given columns (if any).
+func (m *SqliteManager) upgradeTable(table schemaTable, columns []schemaColumn) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var statements []string
+	if len(columns) > 0 {
+		old := table.name + "_old"
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s", table.name, old),
+			table.sql,
+			fmt.Sprintf("INSERT INTO %s SELECT %s FROM %s", table.name, upgradedColumns(table, columns), old),
+			fmt.Sprintf("DROP TABLE %s", old),
+		)
+	} else {
+		statements = append(statements, table.sql)
+	}
+	statements = append(statements, table.indexes...)
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// upgradedColumns returns the expressions that select the columns of the
+// table of the schema from the existing table with the given columns.
+func upgradedColumns(table schemaTable, columns []schemaColumn) string {
+	existing := make(map[string]bool)
+	for _, column := range columns {
+		existing[column.name] = true
+	}
+
+	var exprs []string
+columnsLoop:
+	for _, column := range table.columns {
+		if existing[column.name] {
+			exprs = append(exprs, column.name)
+			continue
+		}
+		for _, legacy := range legacyColumns {
+			if legacy.table == table.name && legacy.column == column.name && hasColumns(existing, legacy.requires) {
+				exprs = append(exprs, legacy.expr)
+				continue columnsLoop
+			}
+		}
+		switch strings.ToUpper(column.dtype) {
+		case "INTEGER":
+			exprs = append(exprs, "0")
+		case "TEXT":
+			exprs = append(exprs, "''")
+		default:
+			exprs = append(exprs, "NULL")
+		}
+	}
+	return strings.Join(exprs, ", ")
+}
+
+func hasColumns(existing map[string]bool, names []string) bool {
+	for _, name := range names {
+		if !existing[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameColumns(a []schemaColumn, b []schemaColumn) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// schemaTables returns the tables of model.SqliteSchema, as created in an
+// empty database.
+func schemaTables() ([]schemaTable, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	// Each connection would have a database of its own.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(model.SqliteSchema); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT name, sql
+		FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	var tables []schemaTable
+	for rows.Next() {
+		var table schemaTable
+		if err := rows.Scan(&table.name, &table.sql); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range tables {
+		tables[i].columns, err = tableColumns(db, tables[i].name)
+		if err != nil {
+			return nil, err
+		}
+		tables[i].indexes, err = tableIndexes(db, tables[i].name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+// tableColumns returns the columns of the table (none if it does not exist).
+func tableColumns(db *sql.DB, table string) ([]schemaColumn, error) {
+	rows, err := db.Query(`SELECT name, type FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []schemaColumn
+	for rows.Next() {
+		var column schemaColumn
+		if err := rows.Scan(&column.name, &column.dtype); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// tableIndexes returns the statements creating the indexes of the table.
+func tableIndexes(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT sql
+		FROM sqlite_master
+		WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []string
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
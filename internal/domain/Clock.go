@@ -0,0 +1,223 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of the sessions and of the session manager.
+//
+// SystemClock is used when running the bot; FakeClock allows simulating
+// sessions without waiting for them.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// NewTimer creates a Timer that will send the current time on its channel
+	// after the duration has elapsed, unless it is stopped earlier.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a stoppable After.
+type Timer interface {
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing. It returns false if the timer
+	// already fired or was already stopped.
+	Stop() bool
+
+	// Reset changes the timer to fire after the duration. It must be called
+	// only on stopped or fired timers, whose channel has been drained.
+	Reset(d time.Duration)
+}
+
+// SystemClock is the Clock backed by the time package.
+type SystemClock struct{}
+
+var _ Clock = SystemClock{}
+
+func (_ SystemClock) Now() time.Time {
+	return time.Now().Local()
+}
+
+func (_ SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (_ SystemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	timer *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+func (t systemTimer) Reset(d time.Duration) {
+	t.timer.Reset(d)
+}
+
+// FakeClock is a Clock whose time only moves forward when Advance is called.
+//
+// It is safe for concurrent use.
+type FakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+
+	sleepers []fakeSleeper
+	watchers []fakeWatcher
+}
+
+type fakeSleeper struct {
+	until time.Time
+	ch    chan time.Time
+}
+
+type fakeWatcher struct {
+	sleepers int
+	ch       chan struct{}
+}
+
+var _ Clock = &FakeClock{}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	timer := &fakeTimer{clock: c, ch: make(chan time.Time, 1)}
+	c.startTimer(timer, d)
+
+	return timer
+}
+
+// startTimer must be called with the lock held.
+func (c *FakeClock) startTimer(timer *fakeTimer, d time.Duration) {
+	if d <= 0 {
+		timer.ch <- c.now
+		return
+	}
+
+	c.sleepers = append(c.sleepers, fakeSleeper{until: c.now.Add(d), ch: timer.ch})
+	c.notifyWatchers()
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	ch    chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	c := t.clock
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i, sleeper := range c.sleepers {
+		if sleeper.ch == t.ch {
+			c.sleepers = append(c.sleepers[:i], c.sleepers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *fakeTimer) Reset(d time.Duration) {
+	c := t.clock
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.startTimer(t, d)
+}
+
+// Advance moves the clock forward, waking up the goroutines whose wait
+// has elapsed.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+
+	var sleepers []fakeSleeper
+	for _, sleeper := range c.sleepers {
+		if !sleeper.until.After(c.now) {
+			sleeper.ch <- c.now
+		} else {
+			sleepers = append(sleepers, sleeper)
+		}
+	}
+	c.sleepers = sleepers
+}
+
+// Sleepers returns a channel that is closed as soon as at least n goroutines
+// are waiting on the clock.
+//
+// Use it to Advance the clock only once the code under test is waiting on it.
+func (c *FakeClock) Sleepers(n int) <-chan struct{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	watcher := fakeWatcher{sleepers: n, ch: make(chan struct{})}
+	c.watchers = append(c.watchers, watcher)
+	c.notifyWatchers()
+
+	return watcher.ch
+}
+
+// notifyWatchers must be called with the lock held.
+func (c *FakeClock) notifyWatchers() {
+	var watchers []fakeWatcher
+	for _, watcher := range c.watchers {
+		if len(c.sleepers) >= watcher.sleepers {
+			close(watcher.ch)
+		} else {
+			watchers = append(watchers, watcher)
+		}
+	}
+	c.watchers = watchers
+}
@@ -0,0 +1,99 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import "time"
+
+// DayLayout is the layout of the local dates of the chats, which key the
+// daily counters.
+const DayLayout = "2006-01-02"
+
+// MaxDailyGoal is the highest daily goal that can be set.
+const MaxDailyGoal = 100
+
+// MaxStreakDays is how far back in time the streaks are computed.
+const MaxStreakDays = 366
+
+// DayKey returns the date of `t` (in the location of `t`) as a DayLayout key.
+func DayKey(t time.Time) string {
+	return t.Format(DayLayout)
+}
+
+// DailyProgress is the number of pomodoros completed by a chat in a day.
+type DailyProgress struct {
+	// Day is the local date of the chat, formatted with DayLayout.
+	Day       string
+	Pomodoros int
+}
+
+// GoalProgress is how a chat is doing with its daily goal.
+type GoalProgress struct {
+	// Goal is the number of pomodoros to complete each day (0 if unset).
+	Goal int
+
+	// Today is the number of pomodoros completed today.
+	Today int
+
+	// Streak is the number of consecutive days, up to today, in which the
+	// goal was met. Today counts only once the goal is met.
+	Streak int
+
+	// JustReached is true if the goal has been met with the last pomodoro.
+	JustReached bool
+}
+
+// HasGoal returns true if the chat set a daily goal.
+func (p GoalProgress) HasGoal() bool {
+	return p.Goal > 0
+}
+
+// IsReached returns true if the goal has been met today.
+func (p GoalProgress) IsReached() bool {
+	return p.HasGoal() && p.Today >= p.Goal
+}
+
+// ComputeGoalProgress returns the progress toward the goal given the daily
+// counters of the chat (in any order) and the current local time of the chat.
+func ComputeGoalProgress(days []DailyProgress, now time.Time, goal int) GoalProgress {
+	pomodoros := make(map[string]int, len(days))
+	for _, day := range days {
+		pomodoros[day.Day] = day.Pomodoros
+	}
+
+	progress := GoalProgress{
+		Goal:  goal,
+		Today: pomodoros[DayKey(now)],
+	}
+	if !progress.HasGoal() {
+		return progress
+	}
+
+	// A streak is not broken by today until the day is over.
+	year, month, day := now.Date()
+	start := 0
+	if !progress.IsReached() {
+		start = 1
+	}
+	for i := start; i < MaxStreakDays; i++ {
+		date := time.Date(year, month, day-i, 12, 0, 0, 0, now.Location())
+		if pomodoros[DayKey(date)] < goal {
+			break
+		}
+		progress.Streak += 1
+	}
+
+	return progress
+}
@@ -0,0 +1,52 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import "testing"
+
+func TestComputeGoalProgress(t *testing.T) {
+	// testEpoch is Monday 17 October 2022, 09:00 UTC.
+	days := []DailyProgress{
+		{Day: "2022-10-13", Pomodoros: 8},
+		{Day: "2022-10-14", Pomodoros: 4},
+		{Day: "2022-10-15", Pomodoros: 4},
+		{Day: "2022-10-16", Pomodoros: 5},
+	}
+
+	tests := []struct {
+		today    int
+		goal     int
+		expected GoalProgress
+	}{
+		{0, 0, GoalProgress{}},
+		{2, 0, GoalProgress{Today: 2}},
+		// The streak is kept until the day is over.
+		{2, 4, GoalProgress{Goal: 4, Today: 2, Streak: 4}},
+		{4, 4, GoalProgress{Goal: 4, Today: 4, Streak: 5}},
+		{5, 5, GoalProgress{Goal: 5, Today: 5, Streak: 2}},
+		{8, 8, GoalProgress{Goal: 8, Today: 8, Streak: 1}},
+	}
+	for _, test := range tests {
+		progress := ComputeGoalProgress(
+			append(days, DailyProgress{Day: DayKey(testEpoch), Pomodoros: test.today}),
+			testEpoch,
+			test.goal,
+		)
+		if progress != test.expected {
+			t.Fatalf("%d/%d: expected %+v, got %+v", test.today, test.goal, test.expected, progress)
+		}
+	}
+}
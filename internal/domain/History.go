@@ -0,0 +1,67 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import "time"
+
+type PhaseKind string
+
+const (
+	PhaseSprint PhaseKind = "sprint"
+	PhaseRest   PhaseKind = "rest"
+)
+
+type PhaseOutcome string
+
+const (
+	OutcomeCompleted PhaseOutcome = "completed"
+	OutcomeCanceled  PhaseOutcome = "canceled"
+	OutcomeSkipped   PhaseOutcome = "skipped"
+
+	// OutcomeVoided marks a sprint that was restarted from scratch.
+	OutcomeVoided PhaseOutcome = "voided"
+)
+
+// HistoryRecord represents a sprint or a rest that came to an end.
+//
+// Records are append-only: they are never updated once stored.
+type HistoryRecord struct {
+	ChatID ChatID
+	Kind   PhaseKind
+
+	Start time.Time
+	End   time.Time
+
+	// PlannedDuration and ActualDuration are expressed in SECONDS.
+	// ActualDuration does not count the time spent in pause.
+	PlannedDuration int64
+	ActualDuration  int64
+
+	Outcome PhaseOutcome
+}
+
+// FocusStats aggregates the sprints of a chat over a period of time.
+type FocusStats struct {
+	// Pomodoros is the number of sprints that were completed.
+	Pomodoros int
+
+	// FocusTime is the time (in SECONDS) spent in sprints, including the ones
+	// that were not completed.
+	FocusTime int64
+
+	// Interruptions counts the interruptions logged during the sprints.
+	Interruptions InterruptionCount
+}
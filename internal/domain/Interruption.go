@@ -0,0 +1,94 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import (
+	"GoforPomodoro/internal/i18n"
+	"strings"
+	"time"
+)
+
+// InterruptionKind tells whether an interruption came from the person
+// focusing (e.g. the urge to check the phone) or from someone else.
+type InterruptionKind string
+
+const (
+	InterruptionInternal InterruptionKind = "internal"
+	InterruptionExternal InterruptionKind = "external"
+)
+
+// MaxInterruptionNoteLength is the longest note (in runes) that can be
+// attached to an interruption.
+const MaxInterruptionNoteLength = 200
+
+// ParseInterruptionKind returns the kind named by `s` (case insensitive).
+func ParseInterruptionKind(s string) (InterruptionKind, bool) {
+	switch kind := InterruptionKind(strings.ToLower(s)); kind {
+	case InterruptionInternal, InterruptionExternal:
+		return kind, true
+	default:
+		return "", false
+	}
+}
+
+// Interruption represents an interruption logged during a sprint.
+//
+// Interruptions are append-only: they are never updated once stored.
+type Interruption struct {
+	ChatID ChatID
+	Kind   InterruptionKind
+	Note   string
+
+	// At is when the interruption was logged; SprintStart is when the
+	// interrupted sprint began (the Start of its HistoryRecord).
+	At          time.Time
+	SprintStart time.Time
+}
+
+// InterruptionCount counts interruptions by kind.
+type InterruptionCount struct {
+	Internal int
+	External int
+}
+
+// Add counts one more interruption of the given kind.
+func (c *InterruptionCount) Add(kind InterruptionKind) {
+	switch kind {
+	case InterruptionInternal:
+		c.Internal++
+	case InterruptionExternal:
+		c.External++
+	}
+}
+
+func (c InterruptionCount) Total() int {
+	return c.Internal + c.External
+}
+
+func (c InterruptionCount) String() string {
+	return c.Localized(i18n.English())
+}
+
+// Localized Print the count in the language of the localizer, e.g.
+// "3 (1 internal, 2 external)".
+func (c InterruptionCount) Localized(l *i18n.Localizer) string {
+	return l.T("interruption_count", c.Total(), c.Internal, c.External)
+}
+
+// Localized returns the name of the kind in the language of the localizer.
+func (kind InterruptionKind) Localized(l *i18n.Localizer) string {
+	return l.T("interruption_" + string(kind))
+}
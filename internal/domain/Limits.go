@@ -0,0 +1,162 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+// SessionLimit names one of the SessionLimits.
+type SessionLimit string
+
+const (
+	// LimitMaxTotal is the longest a session can last (in minutes), rests
+	// included.
+	LimitMaxTotal SessionLimit = "total"
+
+	// LimitMaxSprint and LimitMinSprint bound the duration of each sprint
+	// (in minutes and seconds respectively).
+	LimitMaxSprint SessionLimit = "sprint"
+	LimitMinSprint SessionLimit = "min_sprint"
+
+	// LimitMaxSprints is the largest number of sprints of a session.
+	LimitMaxSprints SessionLimit = "sprints"
+
+	// LimitMaxUnspecified is the longest a session with no set number of
+	// sprints can last (in minutes): it finishes once another sprint would
+	// not fit.
+	LimitMaxUnspecified SessionLimit = "unspecified"
+)
+
+// SessionLimitNames lists all the limits, in the order they are shown.
+var SessionLimitNames = []SessionLimit{
+	LimitMaxTotal,
+	LimitMaxSprint,
+	LimitMinSprint,
+	LimitMaxSprints,
+	LimitMaxUnspecified,
+}
+
+// ParseSessionLimit returns the limit with the given name.
+func ParseSessionLimit(name string) (SessionLimit, bool) {
+	for _, limit := range SessionLimitNames {
+		if string(limit) == name {
+			return limit, true
+		}
+	}
+	return "", false
+}
+
+// Unit returns the unit the values of the limit are expressed in.
+func (limit SessionLimit) Unit() string {
+	switch limit {
+	case LimitMinSprint:
+		return "seconds"
+	case LimitMaxSprints:
+		return "pomodoros"
+	default:
+		return "minutes"
+	}
+}
+
+// SessionLimits bound the sessions that can be set. Zero fields are unset:
+// see WithDefaults.
+type SessionLimits struct {
+	MaxTotalMinutes       int
+	MaxSprintMinutes      int
+	MinSprintSeconds      int
+	MaxSprints            int
+	MaxUnspecifiedMinutes int
+}
+
+// DefaultSessionLimits are the limits used when neither the bot nor the chat
+// set them.
+var DefaultSessionLimits = SessionLimits{
+	MaxTotalMinutes:       48 * 60,
+	MaxSprintMinutes:      24 * 60,
+	MinSprintSeconds:      10,
+	MaxSprints:            100,
+	MaxUnspecifiedMinutes: 48 * 60,
+}
+
+// Get returns the value of a limit (0 if unset).
+func (l SessionLimits) Get(limit SessionLimit) int {
+	switch limit {
+	case LimitMaxTotal:
+		return l.MaxTotalMinutes
+	case LimitMaxSprint:
+		return l.MaxSprintMinutes
+	case LimitMinSprint:
+		return l.MinSprintSeconds
+	case LimitMaxSprints:
+		return l.MaxSprints
+	case LimitMaxUnspecified:
+		return l.MaxUnspecifiedMinutes
+	default:
+		return 0
+	}
+}
+
+// Set changes the value of a limit (0 to unset it).
+func (l *SessionLimits) Set(limit SessionLimit, value int) {
+	switch limit {
+	case LimitMaxTotal:
+		l.MaxTotalMinutes = value
+	case LimitMaxSprint:
+		l.MaxSprintMinutes = value
+	case LimitMinSprint:
+		l.MinSprintSeconds = value
+	case LimitMaxSprints:
+		l.MaxSprints = value
+	case LimitMaxUnspecified:
+		l.MaxUnspecifiedMinutes = value
+	}
+}
+
+// IsZero returns true if no limit is set.
+func (l SessionLimits) IsZero() bool {
+	return l == SessionLimits{}
+}
+
+// Override returns the limits with the ones set in `o` replacing them.
+func (l SessionLimits) Override(o SessionLimits) SessionLimits {
+	for _, limit := range SessionLimitNames {
+		if value := o.Get(limit); value > 0 {
+			l.Set(limit, value)
+		}
+	}
+	return l
+}
+
+// WithDefaults returns the limits with DefaultSessionLimits in place of the
+// unset ones.
+func (l SessionLimits) WithDefaults() SessionLimits {
+	return DefaultSessionLimits.Override(l)
+}
+
+// UnspecifiedSessionOver returns true if the session has no set number of
+// sprints and another one would take it beyond LimitMaxUnspecified.
+//
+// It must be called when a sprint ends, before DecreaseSprintDuration.
+func (l SessionLimits) UnspecifiedSessionOver(s *Session) bool {
+	if !s.IsSprintDurationUnspecified() || l.MaxUnspecifiedMinutes <= 0 {
+		return false
+	}
+
+	// During the n-th sprint, SprintDuration is UnspecifiedSprintCardinality-n.
+	sprintsDone := UnspecifiedSprintCardinality - s.GetSprintDuration()
+
+	sdd := SessionDefaultDataFromSession(s)
+	sdd.Plan = nil
+	sdd.SprintDurationSet = sprintsDone + 1
+	return sdd.CalculateSessionTimeInSeconds() > int64(l.MaxUnspecifiedMinutes)*60
+}
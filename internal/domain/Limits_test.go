@@ -0,0 +1,128 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import (
+	"github.com/BurntSushi/toml"
+	"testing"
+)
+
+func TestSessionLimitsOverride(t *testing.T) {
+	bot := SessionLimits{MaxSprintMinutes: 120, MaxSprints: 10}
+	chat := SessionLimits{MaxSprintMinutes: 90}
+
+	limits := bot.Override(chat).WithDefaults()
+	expected := DefaultSessionLimits
+	expected.MaxSprintMinutes = 90
+	expected.MaxSprints = 10
+	if limits != expected {
+		t.Fatalf("expected %+v, got %+v", expected, limits)
+	}
+
+	if !(SessionLimits{}).IsZero() || chat.IsZero() {
+		t.Fatalf("only the limits with no value set should be zero")
+	}
+	for _, name := range SessionLimitNames {
+		limit, ok := ParseSessionLimit(string(name))
+		if !ok || limit != name {
+			t.Fatalf("%q should be parsed as a limit", name)
+		}
+	}
+	if _, ok := ParseSessionLimit("forever"); ok {
+		t.Fatalf("\"forever\" should not be a limit")
+	}
+}
+
+func TestUnspecifiedSessionOver(t *testing.T) {
+	clock := NewFakeClock(testEpoch)
+	session := newTestSession(SessionDefaultData{
+		SprintDurationSet:   UnspecifiedSprintCardinality,
+		PomodoroDurationSet: 25 * 60,
+		RestDurationSet:     5 * 60,
+	}, clock)
+	limits := SessionLimits{MaxUnspecifiedMinutes: 60}
+
+	if err := session.Start(); err != nil {
+		t.Fatalf("start: unexpected error %v", err)
+	}
+	// A second sprint ends after 55 minutes.
+	if limits.UnspecifiedSessionOver(session) {
+		t.Fatalf("a second sprint fits within the limit")
+	}
+	if (SessionLimits{}).UnspecifiedSessionOver(session) {
+		t.Fatalf("with no limit the session should never be over")
+	}
+
+	session.DecreaseSprintDuration()
+	if err := session.RestStarted(); err != nil {
+		t.Fatalf("rest: unexpected error %v", err)
+	}
+	if err := session.RestFinished(); err != nil {
+		t.Fatalf("sprint: unexpected error %v", err)
+	}
+	// A third sprint would end after 85 minutes.
+	if !limits.UnspecifiedSessionOver(session) {
+		t.Fatalf("a third sprint goes beyond the limit")
+	}
+
+	finite := newTestSession(DefaultSession(), clock)
+	finite.Start()
+	if (SessionLimits{MaxUnspecifiedMinutes: 1}).UnspecifiedSessionOver(finite) {
+		t.Fatalf("the limit applies only to sessions with no set number of sprints")
+	}
+}
+
+func TestSessionLimitsDecode(t *testing.T) {
+	const appSettings = `
+ApiToken = "token"
+
+[SessionLimits]
+MaxTotalMinutes = 600
+MaxSprintMinutes = 90
+MinSprintSeconds = 30
+MaxSprints = 12
+MaxUnspecifiedMinutes = 480
+`
+	var settings AppSettings
+	metadata, err := toml.Decode(appSettings, &settings)
+	if err != nil {
+		t.Fatalf("decode: unexpected error %v", err)
+	}
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		t.Fatalf("unknown keys: %v", undecoded)
+	}
+	expected := SessionLimits{
+		MaxTotalMinutes:       600,
+		MaxSprintMinutes:      90,
+		MinSprintSeconds:      30,
+		MaxSprints:            12,
+		MaxUnspecifiedMinutes: 480,
+	}
+	if settings.SessionLimits != expected {
+		t.Fatalf("expected %+v, got %+v", expected, settings.SessionLimits)
+	}
+
+	// The limits that are not set keep their default.
+	settings = AppSettings{}
+	if _, err := toml.Decode("[SessionLimits]\nMaxSprints = 12\n", &settings); err != nil {
+		t.Fatalf("decode: unexpected error %v", err)
+	}
+	expected = DefaultSessionLimits
+	expected.MaxSprints = 12
+	if limits := settings.SessionLimits.WithDefaults(); limits != expected {
+		t.Fatalf("expected %+v, got %+v", expected, limits)
+	}
+}
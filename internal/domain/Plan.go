@@ -0,0 +1,126 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PlanStep is a sprint of a SessionPlan together with the rest that follows
+// it. Durations are expressed in SECONDS.
+type PlanStep struct {
+	PomodoroDuration PomodoroDuration
+	RestDuration     RestDuration
+}
+
+// SessionPlan lists the steps of a session whose sprints (and rests) do not
+// all last the same. The rest of the last step is never taken.
+type SessionPlan []PlanStep
+
+// IsZero returns true if there is no plan, i.e. every sprint of the session
+// lasts the same.
+func (p SessionPlan) IsZero() bool {
+	return len(p) == 0
+}
+
+// Step returns the i-th step of the plan; the last step is returned if i is
+// out of range.
+func (p SessionPlan) Step(i int) PlanStep {
+	if p.IsZero() {
+		return PlanStep{}
+	}
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(p) {
+		i = len(p) - 1
+	}
+	return p[i]
+}
+
+// DurationInSeconds returns how long the whole plan lasts, excluding the rest
+// of the last step.
+func (p SessionPlan) DurationInSeconds() int64 {
+	var total int64
+	for i, step := range p {
+		total += int64(step.PomodoroDuration)
+		if i < len(p)-1 {
+			total += int64(step.RestDuration)
+		}
+	}
+	return total
+}
+
+// Encode returns the plan in the format used for persistence, i.e. the steps
+// as "pomodoro/rest" (in seconds) separated by spaces.
+func (p SessionPlan) Encode() string {
+	steps := make([]string, len(p))
+	for i, step := range p {
+		steps[i] = fmt.Sprintf("%d/%d", step.PomodoroDuration, step.RestDuration)
+	}
+	return strings.Join(steps, " ")
+}
+
+// DecodeSessionPlan returns the plan encoded with SessionPlan.Encode.
+func DecodeSessionPlan(encoded string) (SessionPlan, error) {
+	var plan SessionPlan
+	for _, field := range strings.Fields(encoded) {
+		durations := strings.Split(field, "/")
+		if len(durations) != 2 {
+			return nil, fmt.Errorf("malformed plan step %q", field)
+		}
+		pomodoroDuration, err := strconv.ParseInt(durations[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed plan step %q", field)
+		}
+		restDuration, err := strconv.ParseInt(durations[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed plan step %q", field)
+		}
+		plan = append(plan, PlanStep{
+			PomodoroDuration: PomodoroDuration(pomodoroDuration),
+			RestDuration:     RestDuration(restDuration),
+		})
+	}
+	return plan, nil
+}
+
+// String Print the plan in human-readable format (aimed at the user).
+func (p SessionPlan) String() string {
+	steps := make([]string, len(p))
+	for i, step := range p {
+		if i == len(p)-1 || step.RestDuration == 0 {
+			steps[i] = fmt.Sprintf("%dm", step.PomodoroDuration/60)
+		} else {
+			steps[i] = fmt.Sprintf("%dm+%dm", step.PomodoroDuration/60, step.RestDuration/60)
+		}
+	}
+	return strings.Join(steps, ", ")
+}
+
+// ToDefaultData returns the session that runs the plan.
+func (p SessionPlan) ToDefaultData() (sdd SessionDefaultData) {
+	first := p.Step(0)
+
+	sdd.SprintDurationSet = SprintDuration(len(p))
+	sdd.PomodoroDurationSet = first.PomodoroDuration
+	sdd.RestDurationSet = first.RestDuration
+	sdd.Plan = p
+
+	return
+}
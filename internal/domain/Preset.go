@@ -0,0 +1,40 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import "regexp"
+
+// Preset is a session saved by a chat under a name, so that it can be started
+// again without typing it.
+type Preset struct {
+	Name string
+
+	// Pattern is the session as the user typed it, e.g. "50for3rest10" or
+	// "plan 50/10 25/5".
+	Pattern string
+
+	// IsDefault marks the preset started by /default. At most one preset
+	// per chat is the default one.
+	IsDefault bool
+}
+
+var presetNameRegexp = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// IsValidPresetName returns true if the name can be given to a preset:
+// lowercase letters, digits, '-' and '_', at most 32 characters.
+func IsValidPresetName(name string) bool {
+	return presetNameRegexp.MatchString(name)
+}
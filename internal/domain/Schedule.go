@@ -0,0 +1,170 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import (
+	"GoforPomodoro/internal/i18n"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxSchedulesPerChat is how many scheduled starts a chat can have at once.
+const MaxSchedulesPerChat = 10
+
+// ScheduleGracePeriod is how late a one-off scheduled start can still be
+// fired, e.g. because the bot was restarting at the time.
+const ScheduleGracePeriod = 10 * time.Minute
+
+// MaxPendingScheduledStarts is how many scheduled starts can be due at the
+// same time, waiting for the update loop (see AppState.ScheduledStarts).
+const MaxPendingScheduledStarts = 100
+
+// ScheduleRecurrence tells on which days a scheduled start fires.
+type ScheduleRecurrence string
+
+const (
+	RecurrenceOnce    ScheduleRecurrence = "once"
+	RecurrenceDaily   ScheduleRecurrence = "daily"
+	RecurrenceWeekday ScheduleRecurrence = "weekday"
+	RecurrenceWeekend ScheduleRecurrence = "weekend"
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// ParseScheduleRecurrence returns the recurrence named by the user: "day"
+// (or "daily"), "weekday", "weekend", or the name of a day of the week (in
+// full or abbreviated to three letters).
+func ParseScheduleRecurrence(text string) (ScheduleRecurrence, error) {
+	text = strings.ToLower(text)
+
+	switch text {
+	case "day", "daily":
+		return RecurrenceDaily, nil
+	case "weekday", "weekdays", "workday", "workdays":
+		return RecurrenceWeekday, nil
+	case "weekend", "weekends":
+		return RecurrenceWeekend, nil
+	}
+
+	text = strings.TrimSuffix(text, "s")
+	for name := range weekdayNames {
+		if text == name || (len(text) == 3 && strings.HasPrefix(name, text)) {
+			return ScheduleRecurrence(name), nil
+		}
+	}
+	return "", fmt.Errorf("unknown recurrence %q", text)
+}
+
+// Matches returns true if the recurrence fires on the given day of the week.
+func (r ScheduleRecurrence) Matches(weekday time.Weekday) bool {
+	switch r {
+	case RecurrenceDaily:
+		return true
+	case RecurrenceWeekday:
+		return weekday != time.Saturday && weekday != time.Sunday
+	case RecurrenceWeekend:
+		return weekday == time.Saturday || weekday == time.Sunday
+	}
+	day, ok := weekdayNames[string(r)]
+	return ok && day == weekday
+}
+
+// SessionSchedule is a session that starts by itself at a given time, once
+// or on recurring days.
+type SessionSchedule struct {
+	ID       int64
+	ChatID   ChatID
+	SenderID ChatID
+
+	Recurrence ScheduleRecurrence
+	Hour       int
+	Minute     int
+
+	// At is when a RecurrenceOnce schedule fires; it is unused otherwise.
+	At time.Time
+
+	// Pattern is the session to start, as for presets (see Preset.Pattern).
+	// When empty, the session set in the chat is started.
+	Pattern string
+}
+
+// IsRecurring returns true if the schedule fires more than once.
+func (s SessionSchedule) IsRecurring() bool {
+	return s.Recurrence != RecurrenceOnce
+}
+
+// NextRun returns when the schedule fires next, strictly after the given
+// time. Days and times of the day are computed in the location of `after`.
+//
+// A one-off schedule always returns At, which may be in the past.
+func (s SessionSchedule) NextRun(after time.Time) time.Time {
+	if !s.IsRecurring() {
+		return s.At
+	}
+
+	year, month, day := after.Date()
+	for i := 0; i <= 7; i++ {
+		candidate := time.Date(year, month, day+i, s.Hour, s.Minute, 0, 0, after.Location())
+		if candidate.After(after) && s.Recurrence.Matches(candidate.Weekday()) {
+			return candidate
+		}
+	}
+
+	// Never reached with a valid recurrence.
+	return time.Time{}
+}
+
+// NextTimeOfDay returns the first time strictly after `after` at which the
+// clock reads hour:minute (today or tomorrow).
+func NextTimeOfDay(after time.Time, hour int, minute int) time.Time {
+	year, month, day := after.Date()
+	candidate := time.Date(year, month, day, hour, minute, 0, 0, after.Location())
+	if !candidate.After(after) {
+		candidate = time.Date(year, month, day+1, hour, minute, 0, 0, after.Location())
+	}
+	return candidate
+}
+
+// String Print the schedule in human-readable format (aimed at the user).
+func (s SessionSchedule) String() string {
+	return s.StringIn(i18n.English(), time.Local)
+}
+
+// StringIn Print the schedule in human-readable format (aimed at the user),
+// in the language of the localizer and with the times in the given location.
+func (s SessionSchedule) StringIn(l *i18n.Localizer, location *time.Location) string {
+	var when string
+	if s.Recurrence == RecurrenceOnce {
+		when = l.DateTime(s.At.In(location))
+	} else {
+		when = l.T("schedule_every_"+string(s.Recurrence), s.Hour, s.Minute)
+	}
+
+	session := s.Pattern
+	if session == "" {
+		session = l.T("schedule_session_of_chat")
+	}
+	return l.T("schedule", s.ID, when, session)
+}
@@ -0,0 +1,64 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionScheduleNextRun(t *testing.T) {
+	// testEpoch is Monday 17 October 2022, 09:00 UTC.
+	friday := time.Date(2022, time.October, 21, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		recurrence ScheduleRecurrence
+		after      time.Time
+		expected   time.Time
+	}{
+		{RecurrenceDaily, testEpoch.Add(-time.Minute), testEpoch},
+		{RecurrenceDaily, testEpoch, testEpoch.AddDate(0, 0, 1)},
+		{RecurrenceWeekday, friday, time.Date(2022, time.October, 24, 9, 0, 0, 0, time.UTC)},
+		{RecurrenceWeekend, testEpoch, time.Date(2022, time.October, 22, 9, 0, 0, 0, time.UTC)},
+		{"monday", testEpoch, testEpoch.AddDate(0, 0, 7)},
+	}
+	for _, test := range tests {
+		schedule := SessionSchedule{Recurrence: test.recurrence, Hour: 9, Minute: 0}
+		if next := schedule.NextRun(test.after); !next.Equal(test.expected) {
+			t.Fatalf("%s after %v: expected %v, got %v", test.recurrence, test.after, test.expected, next)
+		}
+	}
+}
+
+func TestParseScheduleRecurrence(t *testing.T) {
+	tests := map[string]ScheduleRecurrence{
+		"weekday": RecurrenceWeekday,
+		"Daily":   RecurrenceDaily,
+		"weekend": RecurrenceWeekend,
+		"mon":     "monday",
+		"fridays": "friday",
+	}
+	for text, expected := range tests {
+		recurrence, err := ParseScheduleRecurrence(text)
+		if err != nil || recurrence != expected {
+			t.Fatalf("%q: expected %q, got %q (%v)", text, expected, recurrence, err)
+		}
+	}
+
+	if _, err := ParseScheduleRecurrence("someday"); err == nil {
+		t.Fatalf("\"someday\" should not be a valid recurrence")
+	}
+}
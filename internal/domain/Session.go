@@ -16,9 +16,11 @@
 package domain
 
 import (
+	"GoforPomodoro/internal/i18n"
 	"GoforPomodoro/internal/utils"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 )
 
@@ -29,6 +31,36 @@ type DispatchAction struct {
 	Resumed      bool
 	RestStarted  bool
 	RestFinished bool
+	Skipped      bool
+
+	// Extended asks to push the end of the current sprint (or rest) forward
+	// by Extension, as long as the phase is not extended by more than
+	// MaxExtension overall.
+	Extended     bool
+	Extension    time.Duration
+	MaxExtension time.Duration
+
+	// Warning asks to warn that the current sprint (or rest) is about to
+	// end, WarningLead before its end.
+	Warning     bool
+	WarningLead time.Duration
+
+	// Break asks to end the overtime of a session in flow mode and to take
+	// a rest.
+	Break bool
+
+	// Advance confirms the start of the next sprint of a session that is
+	// waiting for it; ReadyTimeout pauses such a session since nobody did.
+	Advance      bool
+	ReadyTimeout bool
+
+	// Restarted asks to void the current sprint and to start it over.
+	Restarted bool
+
+	// Interrupted asks to log an interruption of the current sprint.
+	Interrupted      bool
+	InterruptionKind InterruptionKind
+	InterruptionNote string
 }
 
 type SprintDuration int
@@ -37,6 +69,7 @@ type RestDuration int64
 
 const UnspecifiedSprintCardinality = -100
 const DefaultRestTime = 5 * 60
+const DefaultLongRestInterval = 4
 
 func (d SprintDuration) ToInt() int {
 	return int(d)
@@ -72,39 +105,86 @@ type SessionData struct {
 	// duration for the session.
 	RestDuration
 
-	IsRest     bool
-	IsPaused   bool
-	IsCancel   bool
-	IsFinished bool
+	// PhaseExtension is how much the current sprint (or rest) has been
+	// extended, expressed in SECONDS.
+	PhaseExtension int64
+
+	// PlanStep is the index of the current step of the session plan (if
+	// any). During a sprint, the upcoming rest belongs to the same step.
+	PlanStep int
+
+	// PhaseWarned is true once the user has been warned that the current
+	// sprint (or rest) is about to end.
+	PhaseWarned bool
+
+	// FocusDuration is how long (in SECONDS) the last sprint actually went
+	// on, overtime included, when the session is in flow mode.
+	FocusDuration int64
+
+	// Interruptions counts the interruptions logged during the sprints of
+	// the session.
+	Interruptions InterruptionCount
+
+	State SessionState
 }
 
 type SessionDefaultData struct {
 	SprintDurationSet   SprintDuration
 	PomodoroDurationSet PomodoroDuration
 	RestDurationSet     RestDuration
+
+	// LongRestDurationSet is the duration (in SECONDS) of the long rest taken
+	// every LongRestInterval sprints. Zero means no long rests.
+	LongRestDurationSet RestDuration
+	LongRestInterval    SprintDuration
+
+	// Plan lists the sprint and rest durations of each step, when they are
+	// not all the same. In that case, the durations above are the ones of
+	// the first step.
+	Plan SessionPlan
+
+	// Flow is true if sprints do not end by themselves: once their time is
+	// up, the session goes in overtime until the user takes a break, and the
+	// rest is proportional to the actual focus time.
+	Flow bool
 }
 
 func SessionDefaultDataFromSession(s *Session) (sdd SessionDefaultData) {
 	sdd.PomodoroDurationSet = s.GetPomodoroDurationSet()
 	sdd.RestDurationSet = s.GetRestDurationSet()
 	sdd.SprintDurationSet = s.GetSprintDurationSet()
+	sdd.LongRestDurationSet = s.GetLongRestDurationSet()
+	sdd.LongRestInterval = s.GetLongRestInterval()
+	sdd.Plan = s.GetPlan()
+	sdd.Flow = s.IsFlow()
+	if !sdd.Plan.IsZero() {
+		sdd.PomodoroDurationSet = sdd.Plan.Step(0).PomodoroDuration
+		sdd.RestDurationSet = sdd.Plan.Step(0).RestDuration
+	}
 
 	return
 }
 
+// HasLongRest returns true if the session alternates short rests with long
+// ones.
+func (sdd SessionDefaultData) HasLongRest() bool {
+	return sdd.LongRestDurationSet > 0 && sdd.LongRestInterval > 0
+}
+
 func (sdd SessionDefaultData) ToInitData() (sid SessionInitData) {
 	sid.SprintDurationSet = sdd.SprintDurationSet
 	sid.PomodoroDurationSet = sdd.PomodoroDurationSet
 	sid.RestDurationSet = sdd.RestDurationSet
+	sid.LongRestDurationSet = sdd.LongRestDurationSet
+	sid.LongRestInterval = sdd.LongRestInterval
+	sid.Plan = sdd.Plan
+	sid.Flow = sdd.Flow
 
 	sid.SprintDuration = sdd.SprintDurationSet
 	sid.PomodoroDuration = sdd.PomodoroDurationSet
 	sid.RestDuration = sdd.RestDurationSet
 
-	sid.IsPaused = true
-	sid.IsRest = false
-	sid.IsFinished = false
-	sid.IsCancel = false
+	sid.State = StatePending
 
 	return
 }
@@ -114,18 +194,25 @@ type SessionInitData struct {
 	SprintDurationSet   SprintDuration
 	PomodoroDurationSet PomodoroDuration
 	RestDurationSet     RestDuration
+	LongRestDurationSet RestDuration
+	LongRestInterval    SprintDuration
+	Plan                SessionPlan
+	Flow                bool
 
 	SprintDuration
 	PomodoroDuration
 	RestDuration
+	PlanStep int
 
 	EndNextSprintTimestamp time.Time
 	EndNextRestTimestamp   time.Time
+	PhaseStartTimestamp    time.Time
+	PhaseExtension         int64
+	PhaseWarned            bool
+	FocusDuration          int64
+	Interruptions          InterruptionCount
 
-	IsRest     bool
-	IsPaused   bool
-	IsCancel   bool
-	IsFinished bool
+	State SessionState
 }
 
 func (sid SessionInitData) ToSession() (s *Session) {
@@ -134,15 +221,21 @@ func (sid SessionInitData) ToSession() (s *Session) {
 	s.sprintDurationSet = sid.SprintDurationSet
 	s.pomodoroDurationSet = sid.PomodoroDurationSet
 	s.restDurationSet = sid.RestDurationSet
+	s.longRestDurationSet = sid.LongRestDurationSet
+	s.longRestInterval = sid.LongRestInterval
+	s.plan = sid.Plan
+	s.flow = sid.Flow
 
 	s.data.SprintDuration = sid.SprintDuration
 	s.data.PomodoroDuration = sid.PomodoroDuration
 	s.data.RestDuration = sid.RestDuration
+	s.data.PhaseExtension = sid.PhaseExtension
+	s.data.PlanStep = sid.PlanStep
+	s.data.PhaseWarned = sid.PhaseWarned
+	s.data.FocusDuration = sid.FocusDuration
+	s.data.Interruptions = sid.Interruptions
 
-	s.data.IsCancel = sid.IsCancel
-	s.data.IsPaused = sid.IsPaused
-	s.data.IsRest = sid.IsRest
-	s.data.IsFinished = sid.IsFinished
+	s.data.State = sid.State
 
 	if !sid.EndNextRestTimestamp.IsZero() {
 		s.endNextRestTimestamp = &sid.EndNextRestTimestamp
@@ -150,6 +243,9 @@ func (sid SessionInitData) ToSession() (s *Session) {
 	if !sid.EndNextSprintTimestamp.IsZero() {
 		s.endNextSprintTimestamp = &sid.EndNextSprintTimestamp
 	}
+	if !sid.PhaseStartTimestamp.IsZero() {
+		s.phaseStartTimestamp = &sid.PhaseStartTimestamp
+	}
 
 	return
 }
@@ -158,15 +254,21 @@ func (s *Session) ToInitData() (sid SessionInitData) {
 	sid.SprintDurationSet = s.sprintDurationSet
 	sid.PomodoroDurationSet = s.pomodoroDurationSet
 	sid.RestDurationSet = s.restDurationSet
+	sid.LongRestDurationSet = s.longRestDurationSet
+	sid.LongRestInterval = s.longRestInterval
+	sid.Plan = s.plan
+	sid.Flow = s.flow
 
 	sid.SprintDuration = s.data.SprintDuration
 	sid.PomodoroDuration = s.data.PomodoroDuration
 	sid.RestDuration = s.data.RestDuration
+	sid.PhaseExtension = s.data.PhaseExtension
+	sid.PlanStep = s.data.PlanStep
+	sid.PhaseWarned = s.data.PhaseWarned
+	sid.FocusDuration = s.data.FocusDuration
+	sid.Interruptions = s.data.Interruptions
 
-	sid.IsPaused = s.data.IsPaused
-	sid.IsRest = s.data.IsRest
-	sid.IsFinished = s.data.IsFinished
-	sid.IsCancel = s.data.IsCancel
+	sid.State = s.data.State
 
 	return
 }
@@ -174,9 +276,26 @@ func (s *Session) ToInitData() (sid SessionInitData) {
 type Session struct {
 	ActionsChannel chan DispatchAction
 
+	// channelCleared is closed by ClearChannel, so that the pending
+	// hand-offs of actions (see ActionHandOff) give up; handOffs counts them.
+	channelCleared chan struct{}
+	handOffs       *sync.WaitGroup
+
+	// clock is the source of time of the session. SystemClock is used when
+	// nil.
+	clock Clock
+
+	// location is the timezone in which the times of the session are shown
+	// to the user. time.Local is used when nil.
+	location *time.Location
+
 	endNextSprintTimestamp *time.Time
 	endNextRestTimestamp   *time.Time
 
+	// phaseStartTimestamp is when the current sprint or rest began (pauses
+	// do not move it).
+	phaseStartTimestamp *time.Time
+
 	// sprintDurationSet represents how many sprints the session is the session
 	// intended to have.
 	//
@@ -204,6 +323,20 @@ type Session struct {
 	// much time is left for the current pomodoro rest in run.
 	restDurationSet RestDuration
 
+	// longRestDurationSet represents the time of the long rest expressed in
+	// SECONDS, taken instead of the regular rest after every
+	// longRestInterval sprints. A zero value disables long rests.
+	longRestDurationSet RestDuration
+	longRestInterval    SprintDuration
+
+	// plan, when set, overrides pomodoroDurationSet and restDurationSet with
+	// the durations of the current step.
+	plan SessionPlan
+
+	// flow is true if the session is in flow mode (see
+	// SessionDefaultData.Flow).
+	flow bool
+
 	data SessionData
 }
 
@@ -221,7 +354,7 @@ func (s *Session) GetRestDuration() RestDuration {
 		return s.data.RestDuration
 	}
 
-	return RestDuration(s.endNextRestTimestamp.Sub(time.Now()).Seconds()) // s.PomodoroDuration
+	return RestDuration(s.endNextRestTimestamp.Sub(s.Clock().Now()).Seconds()) // s.PomodoroDuration
 
 	// return s.RestDuration
 }
@@ -233,9 +366,145 @@ func (s *Session) GetRestDuration() RestDuration {
 // during all the session. If you want to know how much time is left in the
 // rest (if it's rest time), use GetRestDuration instead.
 func (s *Session) GetRestDurationSet() RestDuration {
+	if !s.plan.IsZero() {
+		return s.plan.Step(s.data.PlanStep).RestDuration
+	}
 	return s.restDurationSet
 }
 
+// GetLongRestDurationSet returns the time of duration of a long rest
+// expressed in SECONDS (0 if the session has no long rests).
+func (s *Session) GetLongRestDurationSet() RestDuration {
+	return s.longRestDurationSet
+}
+
+// GetLongRestInterval returns after how many sprints a long rest is taken.
+func (s *Session) GetLongRestInterval() SprintDuration {
+	return s.longRestInterval
+}
+
+// HasLongRest returns true if the session alternates short rests with long
+// ones.
+func (s *Session) HasLongRest() bool {
+	return s.longRestDurationSet > 0 && s.longRestInterval > 0
+}
+
+// CompletedSprints returns how many sprints of the session have been
+// completed so far.
+func (s *Session) CompletedSprints() int {
+	// SprintDuration is decreased once at the start of the session and then
+	// once per completed sprint; this holds for unspecified sessions too.
+	return int(s.sprintDurationSet-s.data.SprintDuration) - 1
+}
+
+// isLongRestAfter returns true if the rest following the given number of
+// completed sprints is a long one.
+func (s *Session) isLongRestAfter(completedSprints int) bool {
+	return s.HasLongRest() &&
+		completedSprints > 0 &&
+		completedSprints%int(s.longRestInterval) == 0
+}
+
+// IsLongRest returns true if the current (or, during a sprint, the upcoming)
+// rest is a long one.
+func (s *Session) IsLongRest() bool {
+	completed := s.CompletedSprints()
+	if !s.IsRest() {
+		completed += 1
+	}
+	return s.isLongRestAfter(completed)
+}
+
+// GetCurrentRestDurationSet returns the set duration (in SECONDS) of the
+// current rest, or of the upcoming one if the session is in a sprint.
+//
+// This is either GetRestDurationSet or GetLongRestDurationSet.
+//
+// In flow mode, the current rest is scaled in proportion to the actual focus
+// time of the sprint before it.
+func (s *Session) GetCurrentRestDurationSet() RestDuration {
+	rest := s.GetRestDurationSet()
+	if s.IsLongRest() {
+		rest = s.longRestDurationSet
+	}
+	if s.IsRest() && s.flow && s.data.FocusDuration > 0 && s.GetPomodoroDurationSet() > 0 {
+		rest = RestDuration(int64(rest) * s.data.FocusDuration / int64(s.GetPomodoroDurationSet()))
+	}
+	return rest
+}
+
+// IsFlow returns true if the session is in flow mode.
+func (s *Session) IsFlow() bool {
+	return s.flow
+}
+
+// GetFocusDuration returns how long (in SECONDS) the last sprint actually
+// went on, in flow mode.
+func (s *Session) GetFocusDuration() int64 {
+	return s.data.FocusDuration
+}
+
+// GetInterruptions returns the interruptions logged during the sprints of the
+// session.
+func (s *Session) GetInterruptions() InterruptionCount {
+	return s.data.Interruptions
+}
+
+// GetOvertime returns how long the current sprint has gone on past its end.
+func (s *Session) GetOvertime() time.Duration {
+	if s.data.State != StateOvertime && !(s.data.State == StatePausedSprint && s.data.PomodoroDuration < 0) {
+		return 0
+	}
+	return -time.Duration(s.GetPomodoroDuration()) * time.Second
+}
+
+// pomodoroDurationOf returns the set duration (in SECONDS) of the i-th sprint
+// of the session (starting from 0).
+func (s *Session) pomodoroDurationOf(sprint int) PomodoroDuration {
+	if !s.plan.IsZero() {
+		return s.plan.Step(sprint).PomodoroDuration
+	}
+	return s.pomodoroDurationSet
+}
+
+// restDurationAfter returns the set duration (in SECONDS) of the rest that
+// follows the i-th sprint of the session (starting from 0).
+func (s *Session) restDurationAfter(sprint int) RestDuration {
+	if !s.plan.IsZero() {
+		return s.plan.Step(sprint).RestDuration
+	}
+	if s.isLongRestAfter(sprint + 1) {
+		return s.longRestDurationSet
+	}
+	return s.restDurationSet
+}
+
+// SessionEndTime returns when the last sprint of the session will end, if it
+// is not paused, skipped or extended in the meantime.
+//
+// It returns false if the session is not running, is in flow mode or its
+// number of sprints is unspecified.
+func (s *Session) SessionEndTime() (time.Time, bool) {
+	if !s.State().IsRunning() || s.IsSprintDurationUnspecified() || s.flow {
+		return time.Time{}, false
+	}
+
+	completed := s.CompletedSprints()
+	last := completed + int(s.data.SprintDuration)
+
+	var left int64
+	if s.IsRest() {
+		left = int64(s.GetRestDuration()) + int64(s.pomodoroDurationOf(completed))
+	} else {
+		left = int64(s.GetPomodoroDuration())
+	}
+	for i := completed; i < last; i++ {
+		left += int64(s.restDurationAfter(i)) + int64(s.pomodoroDurationOf(i+1))
+	}
+
+	return s.Clock().Now().Add(time.Duration(left) * time.Second), true
+}
+
 // GetPomodoroDuration returns how much time (in SECONDS) the actual sprint
 // will go on before its end.
 //
@@ -250,7 +519,7 @@ func (s *Session) GetPomodoroDuration() PomodoroDuration {
 		return s.data.PomodoroDuration
 	}
 
-	return PomodoroDuration(s.endNextSprintTimestamp.Sub(time.Now()).Seconds())
+	return PomodoroDuration(s.endNextSprintTimestamp.Sub(s.Clock().Now()).Seconds())
 }
 
 // GetPomodoroDurationSet returns the time of duration of a pomodoro
@@ -260,9 +529,34 @@ func (s *Session) GetPomodoroDuration() PomodoroDuration {
 // during all the session. If you want to know how much time is left in this
 // sprint, use GetPomodoroDuration instead.
 func (s *Session) GetPomodoroDurationSet() PomodoroDuration {
+	if !s.plan.IsZero() {
+		return s.plan.Step(s.data.PlanStep).PomodoroDuration
+	}
 	return s.pomodoroDurationSet
 }
 
+// GetPlan returns the plan of the session (empty if every sprint lasts the
+// same).
+func (s *Session) GetPlan() SessionPlan {
+	return s.plan
+}
+
+// GetPlanStep returns the index of the current step of the session plan.
+func (s *Session) GetPlanStep() int {
+	return s.data.PlanStep
+}
+
+// AdvancePlanStep Move the session to the next step of its plan, if any.
+// It should be called when a rest ends, before RestFinished.
+// This method modifies Session data structures, so should be used
+// in a context where it is actually safe to do so.
+func (s *Session) AdvancePlanStep() {
+	if s.plan.IsZero() || s.data.PlanStep >= len(s.plan)-1 {
+		return
+	}
+	s.data.PlanStep += 1
+}
+
 // GetSprintDuration returns how many sprints the session are left.
 //
 // (Decreases while the session goes on)
@@ -275,6 +569,12 @@ func (s *Session) SprintDurationFinished() bool {
 		s.data.SprintDuration < 0
 }
 
+// IsLastSprint returns true if the session will be finished once the
+// current sprint ends.
+func (s *Session) IsLastSprint() bool {
+	return !s.IsSprintDurationUnspecified() && s.data.SprintDuration <= 0
+}
+
 func (s *Session) IsSprintDurationUnspecified() bool {
 	return s.data.SprintDuration <= UnspecifiedSprintCardinality
 }
@@ -287,7 +587,7 @@ func (s *Session) GetSprintDurationSet() SprintDuration {
 
 // IsRest returns true if it is rest time for the session.
 func (s *Session) IsRest() bool {
-	return s.data.IsRest
+	return s.data.State.IsRest()
 }
 
 // DefaultSession Return a default session.
@@ -302,10 +602,41 @@ func DefaultSession() SessionDefaultData {
 	}
 }
 
+// Clock returns the source of time of the session.
+func (s *Session) Clock() Clock {
+	if s.clock == nil {
+		return SystemClock{}
+	}
+	return s.clock
+}
+
+// SetClock set the source of time of the session. It should be called
+// before the session is started.
+func (s *Session) SetClock(clock Clock) *Session {
+	s.clock = clock
+	return s
+}
+
+// Location returns the timezone in which the times of the session are shown.
+func (s *Session) Location() *time.Location {
+	if s.location == nil {
+		return time.Local
+	}
+	return s.location
+}
+
+// SetLocation set the timezone in which the times of the session are shown.
+func (s *Session) SetLocation(location *time.Location) *Session {
+	s.location = location
+	return s
+}
+
 // InitChannel initialize ActionsChannel attribute; currently done with a
 // buffer of 10 elements.
 func (s *Session) InitChannel() *Session {
 	s.ActionsChannel = make(chan DispatchAction, 10)
+	s.channelCleared = make(chan struct{})
+	s.handOffs = new(sync.WaitGroup)
 	return s
 }
 
@@ -322,17 +653,19 @@ func (s *Session) assignTimestamps() {
 	var pomodoroDurationTime time.Duration = 0
 	var restDurationTime time.Duration = 0
 
+	now := s.Clock().Now()
+
 	if s.IsRest() {
 		restDurationTime = time.Second * time.Duration(s.data.RestDuration)
 
-		s.endNextRestTimestamp = utils.TimePtr(time.Now().Local().Add(restDurationTime))
+		s.endNextRestTimestamp = utils.TimePtr(now.Add(restDurationTime))
 	} else {
 		pomodoroDurationTime = time.Second * time.Duration(s.data.PomodoroDuration)
-		restDurationTime = time.Second * time.Duration(s.restDurationSet)
+		restDurationTime = time.Second * time.Duration(s.GetCurrentRestDurationSet())
 
-		s.endNextSprintTimestamp = utils.TimePtr(time.Now().Local().Add(pomodoroDurationTime))
+		s.endNextSprintTimestamp = utils.TimePtr(now.Add(pomodoroDurationTime))
 
-		s.endNextRestTimestamp = utils.TimePtr(time.Now().Local().Add(pomodoroDurationTime + restDurationTime))
+		s.endNextRestTimestamp = utils.TimePtr(now.Add(pomodoroDurationTime + restDurationTime))
 	}
 }
 
@@ -346,6 +679,30 @@ func (s *Session) WritingActionChannel() chan<- DispatchAction {
 	return s.ActionsChannel
 }
 
+// ActionHandOff returns a function sending actions to the current
+// ActionsChannel without blocking the caller (e.g., the timers): while the
+// channel is full, each action is sent by a new goroutine instead, which gives
+// up only once the channel is cleared (see ClearChannel).
+func (s *Session) ActionHandOff() func(action DispatchAction) {
+	actionsChannel, cleared, handOffs := s.ActionsChannel, s.channelCleared, s.handOffs
+	return func(action DispatchAction) {
+		select {
+		case actionsChannel <- action:
+			return
+		default:
+		}
+
+		handOffs.Add(1)
+		go func() {
+			defer handOffs.Done()
+			select {
+			case actionsChannel <- action:
+			case <-cleared:
+			}
+		}()
+	}
+}
+
 // IsZero Returns true if this session object was instantiated but not
 // meaningfully initialized.
 func (s *Session) IsZero() bool {
@@ -353,14 +710,20 @@ func (s *Session) IsZero() bool {
 }
 
 // String Print the state's session in human-readable format (aimed at the
-// user).
+// user), in English.
 func (s *Session) String() string {
+	return s.Localized(i18n.English())
+}
+
+// Localized Print the state's session in human-readable format (aimed at the
+// user), in the language of the localizer.
+func (s *Session) Localized(l *i18n.Localizer) string {
 	if s == nil {
 		return "nil"
 	}
 
 	if s.GetPomodoroDurationSet() == 0 {
-		return "No session"
+		return l.T("session_none")
 	}
 
 	var middleStr string
@@ -368,31 +731,66 @@ func (s *Session) String() string {
 	if s.IsRest() {
 		sprintDuration += 1
 
-		middleStr = fmt.Sprintf("\nTime for current rest remaining: %s", utils.NiceTimeFormatting(s.GetRestDuration().Seconds()))
+		middleStr = l.T("session_rest_left", l.Duration(int64(s.GetRestDuration().Seconds())))
+	} else if s.State() == StateReady {
+		sprintDuration += 1
+
+		middleStr = l.T("session_waiting_next")
+	} else if overtime := s.GetOvertime(); overtime > 0 {
+		middleStr = l.T("session_overtime", l.Duration(int64(overtime.Seconds())))
 	} else {
-		middleStr = fmt.Sprintf("\nTime for current pomodoro remaining: %s", utils.NiceTimeFormatting(s.GetPomodoroDuration().Seconds()))
+		middleStr = l.T("session_sprint_left", l.Duration(int64(s.GetPomodoroDuration().Seconds())))
 	}
 
 	var sprintDurationSetStr string
 	var pomodorosRemainingStr string
 	if s.IsSprintDurationUnspecified() {
-		pomodorosRemainingStr = "Unspecified"
+		pomodorosRemainingStr = l.T("session_unspecified")
 		sprintDurationSetStr = "X"
 	} else {
 		pomodorosRemainingStr = fmt.Sprintf("%d", sprintDuration)
 		sprintDurationSetStr = fmt.Sprintf("%d", s.GetSprintDurationSet())
 	}
 
-	return fmt.Sprintf("Session of %s🍅 x %dm + %dm",
-		sprintDurationSetStr, s.GetPomodoroDurationSet()/60, s.GetRestDurationSet()/60) +
-		fmt.Sprintf("\nPomodoros remaining: %s", pomodorosRemainingStr) +
+	var headerStr string
+	if plan := s.GetPlan(); !plan.IsZero() {
+		headerStr = l.T("session_header_plan", sprintDurationSetStr, plan) +
+			l.T("session_plan_step", s.GetPlanStep()+1, len(plan))
+	} else {
+		headerStr = l.T("session_header", sprintDurationSetStr,
+			utils.ShortDuration(int64(s.GetPomodoroDurationSet())), utils.ShortDuration(int64(s.GetRestDurationSet())))
+	}
+
+	if endTime, ok := s.SessionEndTime(); ok {
+		middleStr += l.T("session_ends_at", endTime.In(s.Location()).Format("15:04"))
+	}
+
+	if s.IsFlow() {
+		headerStr += l.T("session_flow")
+	}
+
+	if interruptions := s.GetInterruptions(); interruptions.Total() > 0 {
+		middleStr += l.T("session_interruptions", interruptions.Localized(l))
+	}
+
+	return headerStr +
+		longRestString(l, s.GetLongRestDurationSet(), s.GetLongRestInterval()) +
+		l.T("session_remaining", pomodorosRemainingStr) +
 		middleStr +
-		fmt.Sprintf("\n\nCurrent session state: %s", s.State())
+		l.T("session_current_state", s.State().Localized(l))
 }
 
+// String Print the session in human-readable format (aimed at the user), in
+// English.
 func (sdd SessionDefaultData) String() string {
+	return sdd.Localized(i18n.English())
+}
+
+// Localized Print the session in human-readable format (aimed at the user),
+// in the language of the localizer.
+func (sdd SessionDefaultData) Localized(l *i18n.Localizer) string {
 	if sdd.PomodoroDurationSet == 0 {
-		return "No session"
+		return l.T("session_none")
 	}
 
 	var middleStr string
@@ -401,102 +799,101 @@ func (sdd SessionDefaultData) String() string {
 	var sprintDurationSetStr string
 	var pomodorosRemainingStr string
 	if sdd.SprintDurationSet <= UnspecifiedSprintCardinality {
-		pomodorosRemainingStr = "Unspecified"
+		pomodorosRemainingStr = l.T("session_unspecified")
 		sprintDurationSetStr = "X"
 	} else {
 		pomodorosRemainingStr = fmt.Sprintf("%d", sprintDuration)
 		sprintDurationSetStr = fmt.Sprintf("%d", sdd.SprintDurationSet)
 	}
 
-	return fmt.Sprintf("Session of %s🍅 x %dm + %dm",
-		sprintDurationSetStr, sdd.PomodoroDurationSet/60, sdd.RestDurationSet/60) +
-		fmt.Sprintf("\nPomodoros remaining: %s", pomodorosRemainingStr) +
+	var headerStr string
+	if !sdd.Plan.IsZero() {
+		headerStr = l.T("session_header_plan", sprintDurationSetStr, sdd.Plan)
+	} else {
+		headerStr = l.T("session_header", sprintDurationSetStr,
+			utils.ShortDuration(int64(sdd.PomodoroDurationSet)), utils.ShortDuration(int64(sdd.RestDurationSet)))
+	}
+
+	if sdd.Flow {
+		headerStr += l.T("session_flow")
+	}
+
+	return headerStr +
+		longRestString(l, sdd.LongRestDurationSet, sdd.LongRestInterval) +
+		l.T("session_remaining", pomodorosRemainingStr) +
 		middleStr +
-		fmt.Sprintf("\n\nCurrent session state: Pending")
+		l.T("session_current_state", StatePending.Localized(l))
+}
+
+// longRestString Print the long rest configuration of a session (if any) in
+// human-readable format.
+func longRestString(l *i18n.Localizer, longRestDurationSet RestDuration, longRestInterval SprintDuration) string {
+	if longRestDurationSet <= 0 || longRestInterval <= 0 {
+		return ""
+	}
+	return l.T("session_long_rest", utils.ShortDuration(int64(longRestDurationSet)), longRestInterval)
 }
 
 // LeftTimeMessage Print in a string in human-readable format (aimed at the
 // user) how much time is left either for task time or for rest.
-func (s *Session) LeftTimeMessage() string {
-	if s.IsPaused() && !s.IsFinished() {
-		return "Pomodoro in pause. (use /resume)"
-	}
-	if s.IsZero() || s.IsCanceled() || s.IsStopped() {
-		return "No running pomodoros!"
+func (s *Session) LeftTimeMessage(l *i18n.Localizer) string {
+	if s.IsZero() {
+		return l.T("left_none")
 	}
-	if s.IsRest() {
-		return "Rest for other " + utils.NiceTimeFormatting(s.GetRestDuration().Seconds())
-	} else {
-		return "Task time: " + utils.NiceTimeFormatting(s.GetPomodoroDuration().Seconds()) + " left."
+	switch s.State() {
+	case StatePausedSprint, StatePausedRest:
+		return l.T("left_paused")
+	case StateRest:
+		if s.IsLongRest() {
+			return l.T("left_long_rest", l.Duration(int64(s.GetRestDuration().Seconds())))
+		}
+		return l.T("left_rest", l.Duration(int64(s.GetRestDuration().Seconds())))
+	case StateSprint:
+		return l.T("left_sprint", l.Duration(int64(s.GetPomodoroDuration().Seconds())))
+	case StateOvertime:
+		return l.T("left_overtime", l.Duration(int64(s.GetOvertime().Seconds())))
+	case StateReady:
+		return l.T("left_ready")
+	default:
+		return l.T("left_none")
 	}
 }
 
+// IsStopped returns true if the timer of the Session is not running.
 func (s *Session) IsStopped() bool {
-	if s.GetPomodoroDuration() <= 0 ||
-		(!s.IsSprintDurationUnspecified() && s.GetSprintDuration() < 0) ||
-		s.data.IsPaused ||
-		s.data.IsCancel ||
-		s.data.IsFinished {
-		return true
-	}
-
-	return false
+	return !s.data.State.IsRunning()
 }
 
 // IsCanceled returns true if Session has been canceled, otherwise false.
 func (s *Session) IsCanceled() bool {
-	return s.data.IsCancel
+	return s.data.State == StateCanceled
 }
 
 // IsPaused returns true if Session has been paused or never started, otherwise false.
 func (s *Session) IsPaused() bool {
-	return s.data.IsPaused
+	return s.data.State.IsPaused()
 }
 
 // IsFinished returns true if Session has been completed, otherwise false.
 // Note that sessions are not expected to be revived after they become
 // finished.
 func (s *Session) IsFinished() bool {
-	return s.data.IsFinished
+	return s.data.State == StateFinished
 }
 
-// State return the Session's state as a string.
-//
-// # The values are
-//
-// "Pending" if the session was never started (and is actually on pause)
-//
-// "Paused" if the session is on pause, and it was started earlier.
-//
-// "Canceled" if the session has been canceled (s.IsCanceled() == true)
-//
-// "Finished" if the session is finished (s.IsFinished() == true)
-//
-// "Stopped" if the session is not running and none result of the above was
-// the state.
-//
-// "Running" if the session is actually running
-func (s *Session) State() string {
-	var stateStr string
-	if s.IsPaused() {
-		if s.GetPomodoroDuration() == s.GetPomodoroDurationSet() &&
-			s.GetSprintDuration() == s.GetSprintDurationSet() &&
-			s.GetRestDuration() == s.GetRestDurationSet() {
-
-			stateStr = "Pending"
-		} else {
-			stateStr = "Paused"
-		}
-	} else if s.IsCanceled() {
-		stateStr = "Canceled"
-	} else if s.IsFinished() {
-		stateStr = "Finished"
-	} else if s.IsStopped() {
-		stateStr = "Stopped"
-	} else {
-		stateStr = "Running"
+// State return the Session's state.
+func (s *Session) State() SessionState {
+	return s.data.State
+}
+
+// transition Move the Session to the next state, if the transition is valid.
+// Otherwise, InvalidTransition is returned and the state is left unchanged.
+func (s *Session) transition(next SessionState) error {
+	if !s.data.State.CanTransitionTo(next) {
+		return InvalidTransition{From: s.data.State, To: next}
 	}
-	return stateStr
+	s.data.State = next
+	return nil
 }
 
 // Pause Prepare a Session to be paused.
@@ -506,51 +903,102 @@ func (s *Session) State() string {
 // At the time of writing, each Session obj in this project is managed by one
 // and only one goroutine. Pause() call is internal to such goroutine,
 // therefore, it should not happen elsewhere.
-func (s *Session) Pause() {
+func (s *Session) Pause() error {
+	next := StatePausedSprint
+	if s.IsRest() {
+		next = StatePausedRest
+	}
+	if !s.data.State.CanTransitionTo(next) {
+		return InvalidTransition{From: s.data.State, To: next}
+	}
+
 	// Cache pomodoro and rest duration. We will use them again to assign new timestamps.
 	s.data.PomodoroDuration = s.GetPomodoroDuration()
 	s.data.RestDuration = s.GetRestDuration()
 
+	if s.data.State == StateReady {
+		// The next sprint begins only once the session is resumed.
+		s.phaseStartTimestamp = nil
+	}
+
 	// Nil the timestamps (they have to be re-calculated)
 	s.endNextSprintTimestamp = nil
 	s.endNextRestTimestamp = nil
 
-	s.data.IsPaused = true
+	return s.transition(next)
 }
 
-// Cancel Set IsCancel internal attribute to true.
+// Cancel Move the Session to StateCanceled.
 // This method modifies Session data structures, so should be used
 // in a context where it is actually safe to do so.
-func (s *Session) Cancel() {
-	s.data.IsCancel = true
+func (s *Session) Cancel() error {
+	return s.transition(StateCanceled)
 }
 
-// SetFinished Set IsFinished internal attribute to true.
+// SetFinished Move the Session to StateFinished.
 // This method modifies Session data structures, so should be used
 // in a context where it is actually safe to do so.
-func (s *Session) SetFinished() {
-	s.data.IsFinished = true
+func (s *Session) SetFinished() error {
+	return s.transition(StateFinished)
+}
+
+// ResumedState returns the state the Session moves to once it is resumed.
+func (s *Session) ResumedState() (SessionState, error) {
+	switch s.data.State {
+	case StatePausedSprint:
+		if s.flow && s.data.PomodoroDuration <= 0 {
+			// The sprint was paused in overtime.
+			return StateOvertime, nil
+		}
+		return StateSprint, nil
+	case StatePausedRest:
+		return StateRest, nil
+	default:
+		// Only paused sessions can be resumed: pending ones must be started.
+		return s.data.State, InvalidTransition{From: s.data.State, To: StateSprint}
+	}
 }
 
 // Resume Prepare a Session to be resumed.
 // This method modifies Session data structures, so should be used
 // in a context where it is actually safe to do so.
-func (s *Session) Resume() {
-	s.data.IsPaused = false
+func (s *Session) Resume() error {
+	next, err := s.ResumedState()
+	if err != nil {
+		return err
+	}
+
+	if err := s.transition(next); err != nil {
+		return err
+	}
 
 	s.assignTimestamps()
+	if s.phaseStartTimestamp == nil {
+		// The session was paused while waiting for the next sprint.
+		s.phaseStartTimestamp = utils.TimePtr(s.Clock().Now())
+	}
+	return nil
 }
 
 // Start Prepare a Session for the start.
 // This method modifies Session data structures, so should be used
 // in a context where it is actually safe to do so.
-func (s *Session) Start() {
-	s.data.IsPaused = false
-	s.data.IsCancel = false
+func (s *Session) Start() error {
+	if s.isInSprint() {
+		return InvalidTransition{From: s.data.State, To: StateSprint}
+	}
+	if err := s.transition(StateSprint); err != nil {
+		return err
+	}
 
 	s.data.SprintDuration -= 1
 
 	s.assignTimestamps()
+	s.phaseStartTimestamp = utils.TimePtr(s.Clock().Now())
+	s.data.PhaseExtension = 0
+	s.data.PhaseWarned = false
+	s.data.FocusDuration = 0
+	return nil
 }
 
 // RestStarted Prepare a Session object for rest start.
@@ -560,23 +1008,243 @@ func (s *Session) Start() {
 // At the time of writing, each Session obj in this project is managed by one
 // and only one goroutine. RestStarted() call is internal to such goroutine,
 // therefore, it should not happen elsewhere.
-func (s *Session) RestStarted() {
-	s.data.IsRest = true
-	s.data.RestDuration = s.restDurationSet
+func (s *Session) RestStarted() error {
+	if err := s.transition(StateRest); err != nil {
+		return err
+	}
+
+	s.data.RestDuration = s.GetCurrentRestDurationSet()
 	s.assignTimestamps()
+	s.phaseStartTimestamp = utils.TimePtr(s.Clock().Now())
+	s.data.PhaseExtension = 0
+	s.data.PhaseWarned = false
+	return nil
 }
 
-// RestFinished Prepare a Session object for rest end.
+// RestFinished Prepare a Session object for rest end (or, if it was waiting
+// in StateReady, for the start of the next sprint).
 // This method modifies Session data structures, so should be used
 // in a context where it is actually safe to do so.
 //
 // At the time of writing, each Session obj in this project is managed by one
 // and only one goroutine. RestFinished() call is internal to such goroutine,
 // therefore, it should not happen elsewhere.
-func (s *Session) RestFinished() {
-	s.data.IsRest = false
-	s.data.PomodoroDuration = s.pomodoroDurationSet
+func (s *Session) RestFinished() error {
+	if s.isInSprint() {
+		return InvalidTransition{From: s.data.State, To: StateSprint}
+	}
+	if err := s.transition(StateSprint); err != nil {
+		return err
+	}
+
+	s.data.PomodoroDuration = s.GetPomodoroDurationSet()
+	s.assignTimestamps()
+	s.phaseStartTimestamp = utils.TimePtr(s.Clock().Now())
+	s.data.PhaseExtension = 0
+	s.data.PhaseWarned = false
+	s.data.FocusDuration = 0
+	return nil
+}
+
+// RestartSprint Void the current sprint of a Session (or its overtime) and
+// start it over with its full duration. The number of sprints left does not
+// change.
+// This method modifies Session data structures, so should be used
+// in a context where it is actually safe to do so.
+func (s *Session) RestartSprint() error {
+	if !s.isInSprint() {
+		return InvalidTransition{From: s.data.State, To: StateSprint}
+	}
+	if err := s.transition(StateSprint); err != nil {
+		return err
+	}
+
+	s.data.PomodoroDuration = s.GetPomodoroDurationSet()
+	s.assignTimestamps()
+	s.phaseStartTimestamp = utils.TimePtr(s.Clock().Now())
+	s.data.PhaseExtension = 0
+	s.data.PhaseWarned = false
+	s.data.FocusDuration = 0
+	return nil
+}
+
+// isInSprint returns true if the Session is in a sprint that is going on (or
+// in its overtime): only RestartSprint moves it to StateSprint.
+func (s *Session) isInSprint() bool {
+	return s.data.State == StateSprint || s.data.State == StateOvertime
+}
+
+// ReadyStarted Move a Session whose rest is over to StateReady, where it
+// waits for the start of the next sprint to be confirmed with RestFinished.
+// This method modifies Session data structures, so should be used
+// in a context where it is actually safe to do so.
+func (s *Session) ReadyStarted() error {
+	if err := s.transition(StateReady); err != nil {
+		return err
+	}
+
+	s.data.PomodoroDuration = s.GetPomodoroDurationSet()
+	s.endNextSprintTimestamp = nil
+	s.endNextRestTimestamp = nil
+	// Until the next sprint starts, this is when the session started waiting.
+	s.phaseStartTimestamp = utils.TimePtr(s.Clock().Now())
+	s.data.PhaseExtension = 0
+	s.data.PhaseWarned = false
+	return nil
+}
+
+// ReadySince returns when the session started waiting for the next sprint,
+// if it is in StateReady.
+func (s *Session) ReadySince() (time.Time, bool) {
+	if s.data.State != StateReady || s.phaseStartTimestamp == nil {
+		return time.Time{}, false
+	}
+	return *s.phaseStartTimestamp, true
+}
+
+// OvertimeStarted Move a Session in flow mode, whose sprint time is up, to
+// StateOvertime.
+// This method modifies Session data structures, so should be used
+// in a context where it is actually safe to do so.
+func (s *Session) OvertimeStarted() error {
+	if !s.flow {
+		return OperationError{}
+	}
+	return s.transition(StateOvertime)
+}
+
+// Interrupt Log an interruption of the current sprint (or of its overtime),
+// even while it is paused, returning the record to be stored.
+// This method modifies Session data structures, so should be used
+// in a context where it is actually safe to do so.
+func (s *Session) Interrupt(chatId ChatID, kind InterruptionKind, note string) (Interruption, error) {
+	if state := s.State(); state != StateSprint && state != StateOvertime && state != StatePausedSprint {
+		return Interruption{}, NotInSprint{State: state}
+	}
+
+	interruption := Interruption{
+		ChatID: chatId,
+		Kind:   kind,
+		Note:   note,
+		At:     s.Clock().Now(),
+	}
+	if s.phaseStartTimestamp != nil {
+		interruption.SprintStart = *s.phaseStartTimestamp
+	}
+
+	s.data.Interruptions.Add(kind)
+	return interruption, nil
+}
+
+// TakeBreak End the overtime of a Session in flow mode and begin its rest,
+// which lasts in proportion to the actual focus time of the sprint.
+// This method modifies Session data structures, so should be used
+// in a context where it is actually safe to do so.
+func (s *Session) TakeBreak() error {
+	if s.data.State != StateOvertime {
+		return InvalidTransition{From: s.data.State, To: StateRest}
+	}
+
+	focus := int64(s.GetPomodoroDurationSet()) + s.data.PhaseExtension - int64(s.GetPomodoroDuration())
+	if err := s.RestStarted(); err != nil {
+		return err
+	}
+
+	// The rest is set again, now that the focus time is known.
+	s.data.FocusDuration = focus
+	s.data.RestDuration = s.GetCurrentRestDurationSet()
 	s.assignTimestamps()
+	return nil
+}
+
+// Extend Push the end of the current sprint (or rest) forward.
+// This method modifies Session data structures, so should be used
+// in a context where it is actually safe to do so.
+//
+// The current phase cannot be extended by more than maxExtension overall:
+// in that case, ExtensionLimitReached is returned and nothing changes.
+func (s *Session) Extend(extension time.Duration, maxExtension time.Duration) error {
+	if s.data.State == StateOvertime {
+		// The sprint is already going on as long as the user wants.
+		return OperationError{}
+	}
+	if extension <= 0 {
+		return OperationError{}
+	}
+
+	extended := time.Duration(s.data.PhaseExtension) * time.Second
+	if extended+extension > maxExtension {
+		return ExtensionLimitReached{Left: maxExtension - extended}
+	}
+
+	if s.IsRest() {
+		if s.endNextRestTimestamp == nil {
+			return OperationError{}
+		}
+		s.endNextRestTimestamp = utils.TimePtr(s.endNextRestTimestamp.Add(extension))
+	} else {
+		if s.endNextSprintTimestamp == nil {
+			return OperationError{}
+		}
+		// The rest that follows is shifted as well.
+		s.endNextSprintTimestamp = utils.TimePtr(s.endNextSprintTimestamp.Add(extension))
+		if s.endNextRestTimestamp != nil {
+			s.endNextRestTimestamp = utils.TimePtr(s.endNextRestTimestamp.Add(extension))
+		}
+	}
+
+	s.data.PhaseExtension += int64(extension.Seconds())
+	return nil
+}
+
+// GetPhaseExtension returns how much the current sprint (or rest) has been
+// extended.
+func (s *Session) GetPhaseExtension() time.Duration {
+	return time.Duration(s.data.PhaseExtension) * time.Second
+}
+
+// PhaseTimeLeft returns how much time is left before the end of the current
+// sprint (or rest).
+func (s *Session) PhaseTimeLeft() time.Duration {
+	if s.IsRest() {
+		return time.Duration(s.GetRestDuration()) * time.Second
+	}
+	return time.Duration(s.GetPomodoroDuration()) * time.Second
+}
+
+// PhaseEndTimestamp returns when the current sprint (or rest) ends, or nil if
+// the session is not running.
+func (s *Session) PhaseEndTimestamp() *time.Time {
+	if s.IsRest() {
+		return s.endNextRestTimestamp
+	}
+	return s.endNextSprintTimestamp
+}
+
+// PhaseDurationSet returns the set duration of the current sprint (or
+// rest), extensions included.
+func (s *Session) PhaseDurationSet() time.Duration {
+	var seconds int64
+	if s.IsRest() {
+		seconds = int64(s.GetCurrentRestDurationSet())
+	} else {
+		seconds = int64(s.GetPomodoroDurationSet())
+	}
+	return time.Duration(seconds+s.data.PhaseExtension) * time.Second
+}
+
+// IsPhaseWarned returns true if the user has already been warned that the
+// current sprint (or rest) is about to end.
+func (s *Session) IsPhaseWarned() bool {
+	return s.data.PhaseWarned
+}
+
+// SetPhaseWarned Record that the user has been warned that the current sprint
+// (or rest) is about to end, so that it does not happen again.
+// This method modifies Session data structures, so should be used
+// in a context where it is actually safe to do so.
+func (s *Session) SetPhaseWarned() {
+	s.data.PhaseWarned = true
 }
 
 // DecreaseSprintDuration Diminish by 1 the SprintDuration attribute.
@@ -599,6 +1267,10 @@ func (s *Session) DecreaseSprintDuration() {
 // dropped it away). Should be the session be revived (e.g., after a Resume)
 // the channel field should be populated again.
 func (s *Session) ClearChannel() {
+	// The pending hand-offs must leave before the channel is closed.
+	close(s.channelCleared)
+	s.handOffs.Wait()
+
 	close(s.ActionsChannel)
 	s.ActionsChannel = nil
 }
@@ -613,7 +1285,7 @@ func (s *Session) HasSprintEndTimePassed() bool {
 		return false
 	}
 
-	return time.Now().Local().After(*s.endNextSprintTimestamp)
+	return !s.Clock().Now().Before(*s.endNextSprintTimestamp)
 }
 
 // HasRestEndTimePassed
@@ -626,7 +1298,7 @@ func (s *Session) HasRestEndTimePassed() bool {
 		return false
 	}
 
-	return time.Now().Local().After(*s.endNextRestTimestamp)
+	return !s.Clock().Now().Before(*s.endNextRestTimestamp)
 }
 
 func (s *Session) EndNextSprintTimestamp() *time.Time {
@@ -637,20 +1309,69 @@ func (s *Session) EndNextRestTimestamp() *time.Time {
 	return s.endNextRestTimestamp
 }
 
-func (s *Session) CalculateSessionTimeInSeconds() int64 {
-	numberOfSprints := int64(s.GetSprintDurationSet().ToInt())
-	sessionTime := int64(s.GetPomodoroDurationSet().Seconds()) * numberOfSprints
-	if numberOfSprints > 1 {
-		sessionTime += int64(s.GetRestDurationSet().Seconds()) * (numberOfSprints - 1)
+func (s *Session) PhaseStartTimestamp() *time.Time {
+	return s.phaseStartTimestamp
+}
+
+// CurrentPhaseRecord Build the history record of the current sprint (or rest)
+// as if it ended now with the given outcome.
+//
+// It should be called before the Session moves to the next phase, since it
+// reads the time left for the current one.
+func (s *Session) CurrentPhaseRecord(chatId ChatID, outcome PhaseOutcome) HistoryRecord {
+	record := HistoryRecord{
+		ChatID:  chatId,
+		End:     s.Clock().Now(),
+		Outcome: outcome,
 	}
-	return sessionTime
+
+	var remaining int64
+	if s.IsRest() {
+		record.Kind = PhaseRest
+		record.PlannedDuration = int64(s.GetCurrentRestDurationSet())
+		remaining = int64(s.GetRestDuration())
+	} else {
+		record.Kind = PhaseSprint
+		record.PlannedDuration = int64(s.GetPomodoroDurationSet())
+		remaining = int64(s.GetPomodoroDuration())
+	}
+
+	if remaining < 0 && s.data.State != StateOvertime {
+		remaining = 0
+	}
+	record.ActualDuration = record.PlannedDuration + s.data.PhaseExtension - remaining
+	if record.ActualDuration < 0 {
+		record.ActualDuration = 0
+	}
+
+	if s.phaseStartTimestamp != nil {
+		record.Start = *s.phaseStartTimestamp
+	} else {
+		record.Start = record.End.Add(-time.Duration(record.ActualDuration) * time.Second)
+	}
+
+	return record
+}
+
+func (s *Session) CalculateSessionTimeInSeconds() int64 {
+	return SessionDefaultDataFromSession(s).CalculateSessionTimeInSeconds()
 }
 
 func (sdd SessionDefaultData) CalculateSessionTimeInSeconds() int64 {
+	if !sdd.Plan.IsZero() {
+		return sdd.Plan.DurationInSeconds()
+	}
+
 	numberOfSprints := int64(sdd.SprintDurationSet)
 	sessionTime := int64(sdd.PomodoroDurationSet) * numberOfSprints
 	if numberOfSprints > 1 {
-		sessionTime += int64(sdd.RestDurationSet) * (numberOfSprints - 1)
+		numberOfRests := numberOfSprints - 1
+		var numberOfLongRests int64 = 0
+		if sdd.HasLongRest() {
+			numberOfLongRests = numberOfRests / int64(sdd.LongRestInterval)
+		}
+		sessionTime += int64(sdd.RestDurationSet) * (numberOfRests - numberOfLongRests)
+		sessionTime += int64(sdd.LongRestDurationSet) * numberOfLongRests
 	}
 	return sessionTime
 }
@@ -0,0 +1,364 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var testEpoch = time.Date(2022, time.October, 17, 9, 0, 0, 0, time.UTC)
+
+func newTestSession(sdd SessionDefaultData, clock Clock) *Session {
+	return sdd.ToInitData().ToSession().SetClock(clock).InitChannel()
+}
+
+func TestSessionRemainingTimeFollowsClock(t *testing.T) {
+	clock := NewFakeClock(testEpoch)
+	session := newTestSession(DefaultSession(), clock)
+
+	session.Start()
+	clock.Advance(10 * time.Minute)
+	if left := session.GetPomodoroDuration(); left != 15*60 {
+		t.Fatalf("after 10 minutes, 15 minutes should be left, instead %d seconds are left", left)
+	}
+
+	session.Pause()
+	clock.Advance(time.Hour)
+	if left := session.GetPomodoroDuration(); left != 15*60 {
+		t.Fatalf("time should not pass while paused, instead %d seconds are left", left)
+	}
+
+	session.Resume()
+	clock.Advance(5 * time.Minute)
+	if left := session.GetPomodoroDuration(); left != 10*60 {
+		t.Fatalf("after resume and 5 minutes, 10 minutes should be left, instead %d seconds are left", left)
+	}
+	if session.HasSprintEndTimePassed() {
+		t.Fatalf("sprint should not have ended yet")
+	}
+
+	clock.Advance(10*time.Minute + time.Second)
+	if !session.HasSprintEndTimePassed() {
+		t.Fatalf("sprint should have ended")
+	}
+}
+
+func TestSessionLongRest(t *testing.T) {
+	clock := NewFakeClock(testEpoch)
+	sdd := SessionDefaultData{
+		SprintDurationSet:   8,
+		PomodoroDurationSet: 25 * 60,
+		RestDurationSet:     5 * 60,
+		LongRestDurationSet: 20 * 60,
+		LongRestInterval:    4,
+	}
+	session := newTestSession(sdd, clock)
+
+	var rests []RestDuration
+	session.Start()
+	for {
+		clock.Advance(time.Duration(session.GetPomodoroDuration())*time.Second + time.Second)
+		if !session.HasSprintEndTimePassed() {
+			t.Fatalf("sprint should have ended")
+		}
+		session.DecreaseSprintDuration()
+		if session.SprintDurationFinished() {
+			break
+		}
+
+		session.RestStarted()
+		rests = append(rests, session.GetRestDuration())
+
+		clock.Advance(time.Duration(session.GetRestDuration())*time.Second + time.Second)
+		if !session.HasRestEndTimePassed() {
+			t.Fatalf("rest should have ended")
+		}
+		session.RestFinished()
+	}
+
+	expected := []RestDuration{5 * 60, 5 * 60, 5 * 60, 20 * 60, 5 * 60, 5 * 60, 5 * 60}
+	if len(rests) != len(expected) {
+		t.Fatalf("expected %d rests, got %d", len(expected), len(rests))
+	}
+	for i := range expected {
+		if rests[i] != expected[i] {
+			t.Fatalf("rest #%d should last %d seconds, instead it lasts %d", i+1, expected[i], rests[i])
+		}
+	}
+
+	if total := sdd.CalculateSessionTimeInSeconds(); total != (8*25+6*5+20)*60 {
+		t.Fatalf("session should last 250 minutes, instead it lasts %d seconds", total)
+	}
+}
+
+func TestSessionIsLongRest(t *testing.T) {
+	clock := NewFakeClock(testEpoch)
+	sdd := SessionDefaultData{
+		SprintDurationSet:   9,
+		PomodoroDurationSet: 25 * 60,
+		RestDurationSet:     5 * 60,
+		LongRestDurationSet: 20 * 60,
+		LongRestInterval:    3,
+	}
+	session := newTestSession(sdd, clock)
+	sdd.LongRestDurationSet = 0
+	short := newTestSession(sdd, clock)
+
+	session.Start()
+	short.Start()
+	for sprint := 1; sprint < 9; sprint++ {
+		// The rest after every 3rd sprint is long, both while the sprint goes
+		// on (the upcoming rest) and during the rest itself.
+		long := sprint%3 == 0
+		if session.IsLongRest() != long {
+			t.Fatalf("sprint #%d: the upcoming rest should be long: %v", sprint, long)
+		}
+		session.DecreaseSprintDuration()
+		session.RestStarted()
+		if session.IsLongRest() != long {
+			t.Fatalf("rest after sprint #%d should be long: %v", sprint, long)
+		}
+		if rest := session.GetCurrentRestDurationSet(); long && rest != 20*60 || !long && rest != 5*60 {
+			t.Fatalf("rest after sprint #%d lasts %d seconds", sprint, rest)
+		}
+		session.RestFinished()
+
+		short.DecreaseSprintDuration()
+		short.RestStarted()
+		if short.IsLongRest() {
+			t.Fatalf("rest after sprint #%d should not be long without long rests", sprint)
+		}
+		short.RestFinished()
+	}
+}
+
+func TestSessionTransitions(t *testing.T) {
+	clock := NewFakeClock(testEpoch)
+	session := newTestSession(DefaultSession(), clock)
+
+	if session.State() != StatePending {
+		t.Fatalf("a new session should be pending, instead it is %v", session.State())
+	}
+	if err := session.Resume(); err == nil {
+		t.Fatalf("a pending session should not be resumed")
+	}
+
+	steps := []struct {
+		name     string
+		apply    func() error
+		expected SessionState
+	}{
+		{"start", session.Start, StateSprint},
+		{"pause sprint", session.Pause, StatePausedSprint},
+		{"resume sprint", session.Resume, StateSprint},
+		{"begin rest", session.RestStarted, StateRest},
+		{"pause rest", session.Pause, StatePausedRest},
+		{"resume rest", session.Resume, StateRest},
+		{"end rest", session.RestFinished, StateSprint},
+		{"restart sprint", session.RestartSprint, StateSprint},
+		{"cancel", session.Cancel, StateCanceled},
+	}
+	for _, step := range steps {
+		if err := step.apply(); err != nil {
+			t.Fatalf("%s: unexpected error %v", step.name, err)
+		}
+		if session.State() != step.expected {
+			t.Fatalf("%s: expected state %v, got %v", step.name, step.expected, session.State())
+		}
+	}
+
+	err := session.Resume()
+	if _, ok := err.(InvalidTransition); !ok {
+		t.Fatalf("resuming a canceled session should fail with InvalidTransition, got %v", err)
+	}
+	if session.State() != StateCanceled {
+		t.Fatalf("a failed transition should not change the state, instead it is %v", session.State())
+	}
+
+	// Only RestartSprint moves a sprint to another sprint.
+	sprint := newTestSession(DefaultSession(), clock)
+	if err := sprint.Start(); err != nil {
+		t.Fatalf("start: unexpected error %v", err)
+	}
+	if err := sprint.Start(); err == nil {
+		t.Fatalf("a sprint should not be started again")
+	}
+	if err := sprint.RestFinished(); err == nil {
+		t.Fatalf("a sprint should not end a rest")
+	}
+	if sprint.GetSprintDuration() != DefaultSession().SprintDurationSet-1 {
+		t.Fatalf("failed transitions should not count sprints, %d are left", sprint.GetSprintDuration())
+	}
+}
+
+func TestSessionEndTime(t *testing.T) {
+	clock := NewFakeClock(testEpoch)
+	sdd := SessionDefaultData{
+		SprintDurationSet:   4,
+		PomodoroDurationSet: 25 * 60,
+		RestDurationSet:     5 * 60,
+		LongRestDurationSet: 15 * 60,
+		LongRestInterval:    2,
+	}
+	rome, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Skipf("timezone database not available: %v", err)
+	}
+	session := newTestSession(sdd, clock).SetLocation(rome)
+
+	if _, ok := session.SessionEndTime(); ok {
+		t.Fatalf("a pending session should have no end time")
+	}
+
+	// 4 sprints of 25 minutes, two short rests and a long one in between.
+	expected := testEpoch.Add((4*25 + 2*5 + 15) * time.Minute)
+
+	session.Start()
+	clock.Advance(10 * time.Minute)
+	if end, ok := session.SessionEndTime(); !ok || !end.Equal(expected) {
+		t.Fatalf("expected the session to end at %v, got %v", expected, end)
+	}
+
+	clock.Advance(15 * time.Minute)
+	session.DecreaseSprintDuration()
+	session.RestStarted()
+	clock.Advance(time.Minute)
+	if end, ok := session.SessionEndTime(); !ok || !end.Equal(expected) {
+		t.Fatalf("during the rest, expected the session to end at %v, got %v", expected, end)
+	}
+
+	// 09:00 UTC + 2h05m is 13:05 in Rome (CEST).
+	if !strings.Contains(session.String(), "Session ends at 13:05") {
+		t.Fatalf("the session should show its end time in its location, got:\n%s", session.String())
+	}
+}
+
+func TestSessionFlowOvertime(t *testing.T) {
+	clock := NewFakeClock(testEpoch)
+	sdd := DefaultSession()
+	sdd.Flow = true
+	session := newTestSession(sdd, clock)
+
+	if err := session.Start(); err != nil {
+		t.Fatalf("start: unexpected error %v", err)
+	}
+	clock.Advance(25 * time.Minute)
+	if err := session.OvertimeStarted(); err != nil {
+		t.Fatalf("overtime: unexpected error %v", err)
+	}
+
+	// Pausing and resuming during the overtime keeps the session in overtime.
+	clock.Advance(10 * time.Minute)
+	if err := session.Pause(); err != nil {
+		t.Fatalf("pause: unexpected error %v", err)
+	}
+	clock.Advance(time.Hour)
+	if err := session.Resume(); err != nil || session.State() != StateOvertime {
+		t.Fatalf("resume: expected state %v, got %v (%v)", StateOvertime, session.State(), err)
+	}
+	clock.Advance(5 * time.Minute)
+	if overtime := session.GetOvertime(); overtime != 15*time.Minute {
+		t.Fatalf("overtime should be 15 minutes, instead it is %v", overtime)
+	}
+
+	record := session.CurrentPhaseRecord(1, OutcomeCompleted)
+	if record.PlannedDuration != 25*60 || record.ActualDuration != 40*60 {
+		t.Fatalf("expected 25 minutes planned and 40 focused, got %d and %d seconds",
+			record.PlannedDuration, record.ActualDuration)
+	}
+
+	// The rest is in proportion to the focus time: 5 minutes every 25.
+	if err := session.TakeBreak(); err != nil {
+		t.Fatalf("break: unexpected error %v", err)
+	}
+	if rest := session.GetCurrentRestDurationSet(); rest != 8*60 {
+		t.Fatalf("rest should last 8 minutes, instead it lasts %d seconds", rest)
+	}
+	if left := session.GetRestDuration(); left != 8*60 {
+		t.Fatalf("8 minutes of rest should be left, instead %d seconds are left", left)
+	}
+}
+
+func TestSessionInterruptions(t *testing.T) {
+	clock := NewFakeClock(testEpoch)
+	session := newTestSession(DefaultSession(), clock)
+
+	if _, err := session.Interrupt(1, InterruptionInternal, ""); err == nil {
+		t.Fatalf("interruptions should not be logged before the session starts")
+	}
+
+	if err := session.Start(); err != nil {
+		t.Fatalf("start: unexpected error %v", err)
+	}
+	clock.Advance(10 * time.Minute)
+	interruption, err := session.Interrupt(1, InterruptionExternal, "phone call")
+	if err != nil {
+		t.Fatalf("interrupt: unexpected error %v", err)
+	}
+	if !interruption.SprintStart.Equal(testEpoch) || !interruption.At.Equal(testEpoch.Add(10*time.Minute)) {
+		t.Fatalf("interruption logged at %v for the sprint of %v", interruption.At, interruption.SprintStart)
+	}
+	if err := session.Pause(); err != nil {
+		t.Fatalf("pause: unexpected error %v", err)
+	}
+	if _, err := session.Interrupt(1, InterruptionInternal, ""); err != nil {
+		t.Fatalf("interrupt while paused: unexpected error %v", err)
+	}
+	if err := session.Resume(); err != nil {
+		t.Fatalf("resume: unexpected error %v", err)
+	}
+
+	clock.Advance(15 * time.Minute)
+	if err := session.RestStarted(); err != nil {
+		t.Fatalf("rest: unexpected error %v", err)
+	}
+	if _, err := session.Interrupt(1, InterruptionInternal, ""); err == nil {
+		t.Fatalf("interruptions should not be logged during a rest")
+	}
+
+	expected := InterruptionCount{Internal: 1, External: 1}
+	if got := session.GetInterruptions(); got != expected {
+		t.Fatalf("expected %v interruptions, got %v", expected, got)
+	}
+	if !strings.Contains(session.String(), "Interruptions: 2 (1 internal, 1 external)") {
+		t.Fatalf("the interruptions are missing from the session:\n%s", session.String())
+	}
+}
+
+func TestSessionActionHandOff(t *testing.T) {
+	session := newTestSession(DefaultSession(), NewFakeClock(testEpoch))
+	handOff := session.ActionHandOff()
+
+	// The channel holds 10 actions: the last one waits for room in it.
+	for i := 0; i < 11; i++ {
+		handOff(DispatchAction{RestStarted: true})
+	}
+	for i := 0; i < 11; i++ {
+		select {
+		case <-session.ReadingActionChannel():
+		case <-time.After(time.Second):
+			t.Fatalf("only %d actions were delivered", i)
+		}
+	}
+
+	// Once the channel is cleared, the actions still waiting are given up.
+	for i := 0; i < 11; i++ {
+		handOff(DispatchAction{RestFinished: true})
+	}
+	session.ClearChannel()
+}
@@ -0,0 +1,150 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package domain
+
+import (
+	"GoforPomodoro/internal/i18n"
+	"fmt"
+)
+
+// SessionState is the state of a Session.
+//
+// A Session moves from a state to another only along the transitions listed
+// in sessionTransitions.
+type SessionState int
+
+const (
+	// StatePending is the state of a session that was never started.
+	StatePending SessionState = iota
+	StateSprint
+	StateRest
+	StatePausedSprint
+	StatePausedRest
+	StateCanceled
+	StateFinished
+
+	// StateOvertime is the state of a session in flow mode whose sprint is
+	// over but that keeps counting the time until the user takes a break.
+	StateOvertime
+
+	// StateReady is the state of a session whose rest is over, waiting for
+	// someone to confirm the start of the next sprint.
+	StateReady
+)
+
+// sessionTransitions is the table of the valid transitions of a Session.
+// StateSprint and StateOvertime go to StateSprint only when the sprint is
+// restarted (see Session.RestartSprint).
+var sessionTransitions = map[SessionState][]SessionState{
+	StatePending:      {StateSprint, StateCanceled},
+	StateSprint:       {StateSprint, StateRest, StatePausedSprint, StateCanceled, StateFinished, StateOvertime},
+	StateRest:         {StateSprint, StateReady, StatePausedRest, StateCanceled},
+	StateReady:        {StateSprint, StatePausedSprint, StateCanceled},
+	StateOvertime:     {StateSprint, StateRest, StatePausedSprint, StateCanceled, StateFinished},
+	StatePausedSprint: {StateSprint, StateOvertime, StateCanceled},
+	StatePausedRest:   {StateRest, StateCanceled},
+	StateCanceled:     {},
+	StateFinished:     {},
+}
+
+var sessionStateNames = map[SessionState]string{
+	StatePending:      "Pending",
+	StateSprint:       "Running (pomodoro)",
+	StateRest:         "Running (rest)",
+	StateOvertime:     "Running (overtime)",
+	StateReady:        "Waiting for the next pomodoro",
+	StatePausedSprint: "Paused (pomodoro)",
+	StatePausedRest:   "Paused (rest)",
+	StateCanceled:     "Canceled",
+	StateFinished:     "Finished",
+}
+
+// sessionStateKeys are the stable names used to persist the states.
+var sessionStateKeys = map[SessionState]string{
+	StatePending:      "pending",
+	StateSprint:       "sprint",
+	StateRest:         "rest",
+	StateOvertime:     "overtime",
+	StateReady:        "ready",
+	StatePausedSprint: "paused_sprint",
+	StatePausedRest:   "paused_rest",
+	StateCanceled:     "canceled",
+	StateFinished:     "finished",
+}
+
+// String returns the state in human-readable format (aimed at the user).
+func (st SessionState) String() string {
+	if name, ok := sessionStateNames[st]; ok {
+		return name
+	}
+	return fmt.Sprintf("SessionState(%d)", int(st))
+}
+
+// Localized returns the state in human-readable format (aimed at the user),
+// in the language of the localizer.
+func (st SessionState) Localized(l *i18n.Localizer) string {
+	if key := st.Key(); key != "" {
+		return l.T("state_" + key)
+	}
+	return st.String()
+}
+
+// Key returns the name of the state used for persistence.
+func (st SessionState) Key() string {
+	return sessionStateKeys[st]
+}
+
+// ParseSessionState returns the state whose Key is the provided one.
+func ParseSessionState(key string) (SessionState, error) {
+	for st, stKey := range sessionStateKeys {
+		if stKey == key {
+			return st, nil
+		}
+	}
+	return StatePending, fmt.Errorf("unknown session state %q", key)
+}
+
+// CanTransitionTo returns true if a Session can move from this state to the
+// next one.
+func (st SessionState) CanTransitionTo(next SessionState) bool {
+	for _, allowed := range sessionTransitions[st] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRunning returns true if the timer of the session is running.
+func (st SessionState) IsRunning() bool {
+	return st == StateSprint || st == StateRest || st == StateOvertime || st == StateReady
+}
+
+// IsPaused returns true if the session has been paused or never started.
+func (st SessionState) IsPaused() bool {
+	return st == StatePending || st == StatePausedSprint || st == StatePausedRest
+}
+
+// IsRest returns true if the session is in a rest (running or paused).
+func (st SessionState) IsRest() bool {
+	return st == StateRest || st == StatePausedRest
+}
+
+// IsOver returns true if the session was canceled or finished: no transition
+// is possible anymore.
+func (st SessionState) IsOver() bool {
+	return st == StateCanceled || st == StateFinished
+}
@@ -15,6 +15,11 @@
 
 package domain
 
+import (
+	"fmt"
+	"time"
+)
+
 type AlreadySubscribed struct{}
 
 func (_ AlreadySubscribed) Error() string {
@@ -38,3 +43,102 @@ type OperationError struct{}
 func (_ OperationError) Error() string {
 	return "error with this operation right now"
 }
+
+// ExtensionLimitReached is returned when a sprint (or a rest) would be
+// extended beyond the limit. Left is how much it can still be extended.
+type ExtensionLimitReached struct {
+	Left time.Duration
+}
+
+func (_ ExtensionLimitReached) Error() string {
+	return "the limit of extension has been reached"
+}
+
+// InvalidTransition is returned when a Session is asked to move to a state
+// that cannot be reached from the current one (e.g., resuming a canceled
+// session).
+type InvalidTransition struct {
+	From SessionState
+	To   SessionState
+}
+
+func (e InvalidTransition) Error() string {
+	return fmt.Sprintf("invalid session transition from %v to %v", e.From, e.To)
+}
+
+// NotInSprint is returned when something can happen only during a sprint
+// (e.g., logging an interruption), but the session is in another state.
+type NotInSprint struct {
+	State SessionState
+}
+
+func (e NotInSprint) Error() string {
+	return fmt.Sprintf("the session is not in a sprint (%v)", e.State)
+}
+
+// SessionParseError is returned when a session pattern (e.g. /25for4rest5)
+// cannot be parsed. Offset is the position (in bytes) of the offending Token
+// in the pattern; Token is empty if the pattern ended too early.
+type SessionParseError struct {
+	Offset int
+	Token  string
+	Reason string
+}
+
+func (e SessionParseError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at the end of the session pattern", e.Reason)
+	}
+	return fmt.Sprintf("%s at %q (position %d of the session pattern)", e.Reason, e.Token, e.Offset+1)
+}
+
+// SessionLimitExceeded is returned when a session goes beyond one of the
+// SessionLimits. Value (of the session) and Bound (of the limit) are in the
+// unit of the limit.
+type SessionLimitExceeded struct {
+	Limit SessionLimit
+	Value int64
+	Bound int64
+}
+
+func (e SessionLimitExceeded) Error() string {
+	if e.Limit == LimitMinSprint {
+		return fmt.Sprintf("the session is below the %q limit (%d %s, at least %d required)",
+			e.Limit, e.Value, e.Limit.Unit(), e.Bound)
+	}
+	return fmt.Sprintf("the session exceeds the %q limit (%d %s, at most %d allowed)",
+		e.Limit, e.Value, e.Limit.Unit(), e.Bound)
+}
+
+type PersistenceUnavailable struct{}
+
+func (_ PersistenceUnavailable) Error() string {
+	return "no persistence available"
+}
+
+// PresetNotFound is returned when a chat has no preset with the given name.
+type PresetNotFound struct {
+	Name string
+}
+
+func (e PresetNotFound) Error() string {
+	return fmt.Sprintf("there is no preset named %q", e.Name)
+}
+
+// ScheduleNotFound is returned when a chat has no scheduled start with the
+// given ID.
+type ScheduleNotFound struct {
+	ID int64
+}
+
+func (e ScheduleNotFound) Error() string {
+	return fmt.Sprintf("there is no scheduled session #%d", e.ID)
+}
+
+// TooManySchedules is returned when a chat already has MaxSchedulesPerChat
+// scheduled starts.
+type TooManySchedules struct{}
+
+func (_ TooManySchedules) Error() string {
+	return "too many scheduled sessions"
+}
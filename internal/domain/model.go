@@ -16,8 +16,10 @@
 package domain
 
 import (
+	"GoforPomodoro/internal/i18n"
 	"GoforPomodoro/internal/utils"
 	"sync"
+	"time"
 )
 
 type PrivacySettingsVersion int
@@ -43,6 +45,14 @@ func (privacy PrivacySettingsType) HasAcceptedAll() bool {
 		privacy&AcceptedAll != 0
 }
 
+const DefaultMaxPhaseExtension = 30 * time.Minute
+
+// Values of AppSettings.UpdateMode.
+const (
+	UpdateModePolling = "polling"
+	UpdateModeWebhook = "webhook"
+)
+
 type AppSettings struct {
 	ApiToken             string
 	BotName              string
@@ -50,6 +60,39 @@ type AppSettings struct {
 	AdminIds             []ChatID
 	ListenAddressPrivate string
 	ListenPortPrivate    int
+
+	// UpdateMode chooses how the bot gets the messages of the users:
+	// UpdateModePolling (when unset) or UpdateModeWebhook.
+	UpdateMode string
+
+	// WebhookURL is the public HTTPS URL Telegram sends the updates to, in
+	// webhook mode. The public listener serves its path.
+	WebhookURL string
+
+	// WebhookSecret is sent back by Telegram with each update, to prove that
+	// the update comes from it.
+	WebhookSecret string
+
+	// ListenAddressPublic and ListenPortPublic are where the public listener
+	// (the one serving the webhook) listens.
+	ListenAddressPublic string
+	ListenPortPublic    int
+
+	// MaxPhaseExtensionMinutes is how much a single sprint (or rest) can be
+	// extended with /extend. DefaultMaxPhaseExtension is used when unset.
+	MaxPhaseExtensionMinutes int
+
+	// SessionLimits bound the sessions of all the chats (the admins of the
+	// bot can override them for a chat). DefaultSessionLimits are used in
+	// place of the unset ones.
+	SessionLimits SessionLimits
+}
+
+func (s *AppSettings) MaxPhaseExtension() time.Duration {
+	if s.MaxPhaseExtensionMinutes <= 0 {
+		return DefaultMaxPhaseExtension
+	}
+	return time.Duration(s.MaxPhaseExtensionMinutes) * time.Minute
 }
 
 type AppVariables struct {
@@ -73,6 +116,95 @@ type Settings struct {
 	Subscribers     []ChatID
 	PrivacySettings PrivacySettingsType
 	PrivacySettingsVersion
+
+	// Presets, Schedules and DailyProgress are kept here only when the bot
+	// runs without persistence; otherwise they are read from the
+	// PersistenceManager.
+	Presets       []Preset
+	Schedules     []SessionSchedule
+	DailyProgress []DailyProgress
+
+	// DailyGoal is the number of pomodoros the chat wants to complete each
+	// day (0 if unset).
+	DailyGoal int
+
+	// WarnLeadMinutes is how many minutes before the end of each sprint (or
+	// rest) the chat is warned (0 if the chat does not want to be warned).
+	WarnLeadMinutes int
+
+	// LiveStatus is true if the chat wants a status message that is kept up
+	// to date while each sprint (or rest) goes on.
+	LiveStatus bool
+
+	// ManualAdvance is true if, after each rest, the next sprint starts only
+	// once someone confirms it. If nobody does within ReadyTimeoutMinutes
+	// (when not 0), the session is paused.
+	ManualAdvance       bool
+	ReadyTimeoutMinutes int
+
+	// Flow is true if the sessions of the chat are in flow mode (see
+	// SessionDefaultData.Flow), whatever session pattern or preset they are
+	// started from.
+	Flow bool
+
+	// LimitsOverride replaces, for this chat, the session limits set by the
+	// bot (only the limits that are set).
+	LimitsOverride SessionLimits
+
+	// Timezone is the IANA name of the timezone of the chat (e.g.
+	// "Europe/Rome"). The timezone of the server is used when empty.
+	Timezone string
+
+	// Language is the language the chat chose with /language (empty to
+	// follow DetectedLanguage, the one of the Telegram app of the users).
+	Language         string
+	DetectedLanguage string
+}
+
+// Location returns the location of the timezone of the chat.
+func (s *Settings) Location() *time.Location {
+	location, err := LoadTimezone(s.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// ChatLanguage returns the language the bot speaks in the chat (empty for
+// the default one).
+func (s *Settings) ChatLanguage() string {
+	if s.Language != "" {
+		return s.Language
+	}
+	return s.DetectedLanguage
+}
+
+// MaxReadyTimeoutMinutes is the longest wait for the next sprint that can be
+// set before a session is paused.
+const MaxReadyTimeoutMinutes = 24 * 60
+
+// ReadyTimeout returns how long a session waits for the next sprint to be
+// confirmed before being paused (0 if it waits forever).
+func (s *Settings) ReadyTimeout() time.Duration {
+	return time.Duration(s.ReadyTimeoutMinutes) * time.Minute
+}
+
+// MaxWarnLeadMinutes is the highest lead time of the pre-end warnings.
+const MaxWarnLeadMinutes = 30
+
+// WarnLead returns how long before the end of each sprint (or rest) the chat
+// is warned (0 if the chat does not want to be warned).
+func (s *Settings) WarnLead() time.Duration {
+	return time.Duration(s.WarnLeadMinutes) * time.Minute
+}
+
+// LoadTimezone returns the location of the IANA timezone name (e.g.
+// "Europe/Rome"). An empty name is the timezone of the server.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
 }
 
 type PersistenceManager interface {
@@ -83,15 +215,63 @@ type PersistenceManager interface {
 
 	GetActiveChatSettings() ([]utils.Pair[ChatID, *Settings], error)
 
+	AddHistoryRecord(record HistoryRecord) error
+	GetFocusStats(id ChatID, since time.Time) (FocusStats, error)
+
+	AddInterruption(interruption Interruption) error
+
+	GetPresets(id ChatID) ([]Preset, error)
+	StorePreset(id ChatID, preset Preset) error
+	DeletePreset(id ChatID, name string) error
+
+	GetSchedules(id ChatID) ([]SessionSchedule, error)
+	GetAllSchedules() ([]SessionSchedule, error)
+	AddSchedule(schedule SessionSchedule) (int64, error)
+	DeleteSchedule(id ChatID, scheduleId int64) error
+
+	IncrementDailyPomodoros(id ChatID, day string) error
+	GetDailyProgress(id ChatID, since string) ([]DailyProgress, error)
+
 	LockDB()
 	UnlockDB()
 }
 
+// Scheduler runs callbacks at a given time.
+//
+// Each key has at most one callback scheduled: scheduling again with the same
+// key replaces the previous callback.
+type Scheduler interface {
+	Schedule(key any, at time.Time, fire func())
+
+	// Unschedule removes the callback of the key, if any. Once it returns,
+	// the callback is guaranteed not to be running nor to run later.
+	Unschedule(key any)
+}
+
 type AppState struct {
 	DebugMode bool
 
+	// Clock is the source of time of all the sessions.
+	Clock Clock
+
+	// Scheduler dispatches the timed actions of all the running sessions.
+	Scheduler Scheduler
+
 	PersistenceManager PersistenceManager
 
+	// SessionLimits bound the sessions of the chats which do not override
+	// them (see Settings.LimitsOverride).
+	SessionLimits SessionLimits
+
+	// Messages holds the messages of the bot in all its languages (the
+	// built-in ones if nil).
+	Messages *i18n.Bundle
+
+	// ScheduledStarts hands the scheduled sessions that are due over to the
+	// update loop, which starts them in between the updates of the chats.
+	// It holds up to MaxPendingScheduledStarts of them.
+	ScheduledStarts chan SessionSchedule
+
 	UsersSettings     map[ChatID]*Settings
 	UsersSettingsLock sync.RWMutex
 }
@@ -115,6 +295,15 @@ func (appState *AppState) WriteSettings(
 	appState.UsersSettings[chatId] = settings
 }
 
+// GetClock returns the source of time of the application (SystemClock if none
+// was set).
+func (appState *AppState) GetClock() Clock {
+	if appState.Clock == nil {
+		return SystemClock{}
+	}
+	return appState.Clock
+}
+
 //type DispatchServerAction struct {
 //	Shutdown bool
 //}
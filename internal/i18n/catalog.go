@@ -0,0 +1,240 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DefaultLanguage is the language used when a chat has none, and the one
+// whose messages are used when a catalog lacks some.
+const DefaultLanguage = "en"
+
+//go:embed locales/*.toml
+var builtinLocales embed.FS
+
+// Message is the text of a message in a language. Messages that depend on a
+// number have a form for each plural category instead.
+type Message struct {
+	Text   string
+	Plural map[PluralCategory]string
+}
+
+// form returns the text of the message for the plural category.
+func (m Message) form(category PluralCategory) string {
+	if m.Plural == nil {
+		return m.Text
+	}
+	if text, ok := m.Plural[category]; ok {
+		return text
+	}
+	return m.Plural[PluralOther]
+}
+
+// Catalog holds the messages of a language.
+type Catalog struct {
+	// Code is the IETF language tag of the catalog (e.g. "it").
+	Code string
+
+	// Name is the language in the language itself (e.g. "Italiano").
+	Name string
+
+	pluralRule PluralRule
+	messages   map[string]Message
+}
+
+// catalogFile is the content of a catalog file, e.g.
+//
+//	Name = "Italiano"
+//	Plural = "one-other"
+//
+//	[Messages]
+//	session_resumed = "Sessione ripresa!"
+//	duration_minutes = { one = "%d minuto", other = "%d minuti" }
+type catalogFile struct {
+	Name     string
+	Plural   string
+	Messages map[string]interface{}
+}
+
+// parseCatalog Read a catalog file and merge it into the catalog (nil to
+// create a new one).
+func parseCatalog(catalog *Catalog, code string, content string) (*Catalog, error) {
+	var file catalogFile
+	if _, err := toml.Decode(content, &file); err != nil {
+		return nil, err
+	}
+
+	if catalog == nil {
+		catalog = &Catalog{
+			Code:       code,
+			Name:       code,
+			pluralRule: pluralRules[DefaultPluralRule],
+			messages:   make(map[string]Message),
+		}
+	}
+	if file.Name != "" {
+		catalog.Name = file.Name
+	}
+	if file.Plural != "" {
+		rule, ok := pluralRules[file.Plural]
+		if !ok {
+			return nil, fmt.Errorf("unknown plural rule %q", file.Plural)
+		}
+		catalog.pluralRule = rule
+	}
+
+	for key, value := range file.Messages {
+		switch value := value.(type) {
+		case string:
+			catalog.messages[key] = Message{Text: value}
+		case map[string]interface{}:
+			message := Message{Plural: make(map[PluralCategory]string)}
+			for category, text := range value {
+				text, ok := text.(string)
+				if !ok {
+					return nil, fmt.Errorf("message %q: the %q form is not a string", key, category)
+				}
+				message.Plural[PluralCategory(category)] = text
+			}
+			if _, ok := message.Plural[PluralOther]; !ok {
+				return nil, fmt.Errorf("message %q has no %q form", key, PluralOther)
+			}
+			catalog.messages[key] = message
+		default:
+			return nil, fmt.Errorf("message %q is neither a string nor a table of plural forms", key)
+		}
+	}
+	return catalog, nil
+}
+
+// Bundle holds the catalogs of all the languages the bot speaks.
+type Bundle struct {
+	catalogs map[string]*Catalog
+}
+
+// NewBundle returns a bundle with the catalogs built in the bot.
+func NewBundle() *Bundle {
+	bundle := &Bundle{catalogs: make(map[string]*Catalog)}
+
+	entries, err := builtinLocales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		content, err := builtinLocales.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		if err := bundle.add(entry.Name(), string(content)); err != nil {
+			panic(fmt.Sprintf("built-in catalog %s: %v", entry.Name(), err))
+		}
+	}
+	return bundle
+}
+
+// add Merge the catalog file with the given name (e.g. "it.toml") into the
+// bundle.
+func (b *Bundle) add(fileName string, content string) error {
+	code := strings.ToLower(strings.TrimSuffix(fileName, filepath.Ext(fileName)))
+	catalog, err := parseCatalog(b.catalogs[code], code, content)
+	if err != nil {
+		return err
+	}
+	b.catalogs[code] = catalog
+	return nil
+}
+
+// LoadDir Merge into the bundle the catalog files (<language>.toml) found in
+// the directory. A file of a language already in the bundle replaces only the
+// messages it contains. A missing directory is not an error.
+func (b *Bundle) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := b.add(filepath.Base(path), string(content)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Languages returns the catalogs of the bundle, sorted by language code.
+func (b *Bundle) Languages() []*Catalog {
+	var catalogs []*Catalog
+	for _, catalog := range b.bundle().catalogs {
+		catalogs = append(catalogs, catalog)
+	}
+	sort.Slice(catalogs, func(i, j int) bool { return catalogs[i].Code < catalogs[j].Code })
+	return catalogs
+}
+
+// Match returns the language of the bundle that fits the language code sent
+// by Telegram (e.g. "it" for "it-IT").
+func (b *Bundle) Match(languageCode string) (string, bool) {
+	code := strings.ToLower(strings.ReplaceAll(languageCode, "_", "-"))
+	catalogs := b.bundle().catalogs
+	if _, ok := catalogs[code]; ok && code != "" {
+		return code, true
+	}
+	if base, _, found := strings.Cut(code, "-"); found {
+		if _, ok := catalogs[base]; ok {
+			return base, true
+		}
+	}
+	return "", false
+}
+
+// Localizer returns the localizer of the language (of DefaultLanguage if the
+// bundle does not have it).
+func (b *Bundle) Localizer(language string) *Localizer {
+	catalogs := b.bundle().catalogs
+	fallback := catalogs[DefaultLanguage]
+	if catalog, ok := catalogs[language]; ok {
+		return &Localizer{catalog: catalog, fallback: fallback}
+	}
+	return &Localizer{catalog: fallback, fallback: fallback}
+}
+
+// bundle returns the bundle, or the built-in one if it is nil.
+func (b *Bundle) bundle() *Bundle {
+	if b == nil {
+		return builtin
+	}
+	return b
+}
+
+var builtin = NewBundle()
+
+// English returns the localizer of DefaultLanguage with the built-in
+// messages.
+func English() *Localizer {
+	return builtin.Localizer(DefaultLanguage)
+}
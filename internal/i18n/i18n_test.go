@@ -0,0 +1,168 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBuiltinCatalogsHaveTheSameMessages(t *testing.T) {
+	bundle := NewBundle()
+	english := bundle.catalogs[DefaultLanguage]
+
+	for code, catalog := range bundle.catalogs {
+		for key, message := range english.messages {
+			translation, ok := catalog.messages[key]
+			if !ok {
+				t.Errorf("catalog %q lacks message %q", code, key)
+				continue
+			}
+			if (message.Plural == nil) != (translation.Plural == nil) {
+				t.Errorf("catalog %q: message %q should have plural forms only if the English one has them", code, key)
+			}
+		}
+		for key := range catalog.messages {
+			if _, ok := english.messages[key]; !ok {
+				t.Errorf("catalog %q has unknown message %q", code, key)
+			}
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		language string
+		seconds  int64
+		expected string
+	}{
+		{"en", 1, "1 second"},
+		{"en", 10, "10 seconds"},
+		{"en", 100, "2 minutes"},
+		{"en", 60 * 60, "1 hour"},
+		{"en", 61 * 60, "1 hour 1 minute"},
+		{"en", 115 * 60, "1 hour 55 minutes"},
+		{"en", 120 * 60, "2 hours"},
+		{"it", 1, "1 secondo"},
+		{"it", 115 * 60, "1 ora 55 minuti"},
+	}
+	for _, test := range tests {
+		if got := NewBundle().Localizer(test.language).Duration(test.seconds); got != test.expected {
+			t.Errorf("Duration(%d) in %q = %q, expected %q", test.seconds, test.language, got, test.expected)
+		}
+	}
+}
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		rule     string
+		n        int64
+		expected PluralCategory
+	}{
+		{"one-other", 1, PluralOne},
+		{"one-other", 0, PluralOther},
+		{"one-other", 2, PluralOther},
+		{"one-incl-zero-other", 0, PluralOne},
+		{"one-few-many", 1, PluralOne},
+		{"one-few-many", 3, PluralFew},
+		{"one-few-many", 5, PluralMany},
+		{"one-few-many", 12, PluralMany},
+		{"one-few-many", 22, PluralFew},
+		{"other", 1, PluralOther},
+	}
+	for _, test := range tests {
+		if got := pluralRules[test.rule](test.n); got != test.expected {
+			t.Errorf("plural rule %q of %d = %q, expected %q", test.rule, test.n, got, test.expected)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		languageCode string
+		expected     string
+		ok           bool
+	}{
+		{"it", "it", true},
+		{"it-IT", "it", true},
+		{"en_GB", "en", true},
+		{"xx", "", false},
+		{"", "", false},
+	}
+	for _, test := range tests {
+		got, ok := NewBundle().Match(test.languageCode)
+		if got != test.expected || ok != test.ok {
+			t.Errorf("Match(%q) = %q, %v, expected %q, %v", test.languageCode, got, ok, test.expected, test.ok)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"it.toml": "[Messages]\nsession_resumed = \"Si riparte!\"\n",
+		"de.toml": "Name = \"Deutsch\"\n\n[Messages]\nsession_resumed = \"Sitzung fortgesetzt!\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundle := NewBundle()
+	if err := bundle.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+
+	italian := bundle.Localizer("it")
+	if got := italian.T("session_resumed"); got != "Si riparte!" {
+		t.Errorf("the message of the file should replace the built-in one, got %q", got)
+	}
+	if got := italian.T("session_paused"); got != NewBundle().Localizer("it").T("session_paused") {
+		t.Errorf("the messages missing from the file should be kept, got %q", got)
+	}
+
+	german := bundle.Localizer("de")
+	if german.Language() != "de" || german.Name() != "Deutsch" {
+		t.Errorf("new language not loaded: %q (%q)", german.Language(), german.Name())
+	}
+	if got := german.T("session_paused"); got != English().T("session_paused") {
+		t.Errorf("missing messages should be printed in English, got %q", got)
+	}
+
+	if err := bundle.LoadDir(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("a missing directory should not be an error: %v", err)
+	}
+}
+
+func TestLocalizerFallback(t *testing.T) {
+	if got := NewBundle().Localizer("xx").Language(); got != DefaultLanguage {
+		t.Errorf("unknown languages should fall back to %q, got %q", DefaultLanguage, got)
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	date := time.Date(2022, time.October, 17, 9, 5, 0, 0, time.UTC)
+
+	if got := English().DateTime(date); got != "Mon 17 Oct at 09:05" {
+		t.Errorf("DateTime in English = %q", got)
+	}
+	if got := NewBundle().Localizer("it").DateTime(date); got != "lun 17 ott alle 09:05" {
+		t.Errorf("DateTime in Italian = %q", got)
+	}
+}
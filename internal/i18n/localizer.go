@@ -0,0 +1,112 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Localizer prints the messages in a language.
+type Localizer struct {
+	catalog  *Catalog
+	fallback *Catalog
+}
+
+// Language returns the code of the language of the localizer.
+func (l *Localizer) Language() string {
+	return l.catalog.Code
+}
+
+// Name returns the name of the language of the localizer, in the language
+// itself.
+func (l *Localizer) Name() string {
+	return l.catalog.Name
+}
+
+// message returns the message with the given key, looking for it in the
+// catalog of DefaultLanguage if the language lacks it.
+func (l *Localizer) message(key string) (Message, PluralRule, bool) {
+	if message, ok := l.catalog.messages[key]; ok {
+		return message, l.catalog.pluralRule, true
+	}
+	if message, ok := l.fallback.messages[key]; ok {
+		return message, l.fallback.pluralRule, true
+	}
+	log.Printf("[Localizer] missing message %q\n", key)
+	return Message{}, nil, false
+}
+
+// T Print the message with the given key, formatting the arguments as in
+// fmt.Sprintf.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	message, _, ok := l.message(key)
+	if !ok {
+		return key
+	}
+	return sprintf(message.form(PluralOther), args)
+}
+
+// N Print the plural form of the message that fits the number n. The number
+// is the first argument of the message, followed by the others.
+func (l *Localizer) N(key string, n int64, args ...interface{}) string {
+	message, rule, ok := l.message(key)
+	if !ok {
+		return key
+	}
+	return sprintf(message.form(rule(n)), append([]interface{}{n}, args...))
+}
+
+func sprintf(format string, args []interface{}) string {
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Duration Print a duration (in SECONDS) in human-readable format, e.g.
+// "1 hour 30 minutes".
+func (l *Localizer) Duration(seconds int64) string {
+	if seconds >= 60*60 {
+		minutes := int64(math.Ceil(float64(seconds) / 60.0))
+		hours := minutes / 60
+		minutes = minutes % 60
+
+		if minutes == 0 {
+			return l.N("duration_hours", hours)
+		}
+		return l.N("duration_hours", hours) + " " + l.N("duration_minutes", minutes)
+	} else if seconds > 60 {
+		minutes := int64(math.Ceil(float64(seconds) / 60.0))
+		return l.N("duration_minutes", minutes)
+	} else {
+		return l.N("duration_seconds", seconds)
+	}
+}
+
+// DateTime Print a day and a time, e.g. "Mon 2 Jan at 15:04", in the
+// location of t.
+func (l *Localizer) DateTime(t time.Time) string {
+	weekdays := strings.Fields(l.T("weekdays_short"))
+	months := strings.Fields(l.T("months_short"))
+	if len(weekdays) != 7 || len(months) != 12 {
+		return t.Format("Mon 2 Jan 15:04")
+	}
+	return l.T("date_time", weekdays[t.Weekday()], t.Day(), months[t.Month()-1], t.Format("15:04"))
+}
@@ -0,0 +1,65 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package i18n
+
+// PluralCategory names a plural form of a message, as in the Unicode CLDR.
+type PluralCategory string
+
+const (
+	PluralOne   PluralCategory = "one"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// PluralRule returns the plural form to use for the number n.
+type PluralRule func(n int64) PluralCategory
+
+// DefaultPluralRule is the rule of the catalogs which do not name one.
+const DefaultPluralRule = "one-other"
+
+// pluralRules are the rules a catalog can choose from, by name.
+var pluralRules = map[string]PluralRule{
+	// English, Italian, German, Spanish, ...
+	"one-other": func(n int64) PluralCategory {
+		if n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	},
+	// French, Portuguese (Brazil), ...
+	"one-incl-zero-other": func(n int64) PluralCategory {
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	},
+	// Russian, Ukrainian, ...
+	"one-few-many": func(n int64) PluralCategory {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return PluralOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	},
+	// Chinese, Japanese, ...
+	"other": func(n int64) PluralCategory {
+		return PluralOther
+	},
+}
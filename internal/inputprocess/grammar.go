@@ -0,0 +1,353 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package inputprocess
+
+import (
+	"GoforPomodoro/internal/domain"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The grammar of the session patterns (case insensitive, spaces between the
+// tokens are optional):
+//
+//	session  = ["/"] duration ["/" duration] {clause}
+//	clause   = ("for" | "x") (number | letter)
+//	         | "rest" duration
+//	         | "long" duration ["every" number]
+//	duration = number [unit] {number [unit]}
+//	unit     = "h" | "m" | "min" | "s" | "sec"
+//
+// A number without unit is in minutes, unless it follows a number with a
+// unit: then it is in the next smaller unit (e.g. 1h30 is 1h30m). The units
+// of a duration go from hours to seconds. A letter instead of the number of
+// sprints leaves it unspecified.
+//
+// Examples: /25for4rest5, /1h30, /90m x2 rest 15m, /25m/5m x4, /45s.
+
+// maxPatternNumber is the largest number accepted in a pattern, so that the
+// durations cannot overflow (the length of a session is limited anyway).
+const maxPatternNumber = 99999
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenWord
+	tokenSlash
+)
+
+// token is a lexeme of a session pattern. Offset is its position (in bytes)
+// in the pattern.
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// patternKeywords are the words the letters of a pattern are split into,
+// longest first. Letters that do not start a keyword are tokens by themselves.
+var patternKeywords = []string{"every", "long", "rest", "for", "min", "sec", "h", "m", "s", "x"}
+
+var unitSeconds = map[string]int64{
+	"h":   60 * 60,
+	"m":   60,
+	"min": 60,
+	"s":   1,
+	"sec": 1,
+}
+
+// tokenize Split a session pattern into tokens, the last of which is always
+// a tokenEnd.
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+
+	for offset := 0; offset < len(text); {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		switch {
+		case unicode.IsSpace(r):
+			offset += size
+		case r == '/':
+			tokens = append(tokens, token{kind: tokenSlash, text: "/", offset: offset})
+			offset += size
+		case r >= '0' && r <= '9':
+			end := offset
+			for end < len(text) && text[end] >= '0' && text[end] <= '9' {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text[offset:end], offset: offset})
+			offset = end
+		case unicode.IsLetter(r):
+			end := offset
+			for end < len(text) {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if !unicode.IsLetter(r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, splitWords(text[offset:end], offset)...)
+			offset = end
+		default:
+			return nil, domain.SessionParseError{
+				Offset: offset,
+				Token:  string(r),
+				Reason: "unexpected character",
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, offset: len(text)}), nil
+}
+
+// splitWords Split a run of letters, found at `offset` of the pattern, into
+// keywords and single letters (e.g. "forXrest" into "for", "X", "rest").
+func splitWords(letters string, offset int) []token {
+	var tokens []token
+
+	for start := 0; start < len(letters); {
+		rest := letters[start:]
+		length := 0
+		for _, keyword := range patternKeywords {
+			if len(rest) >= len(keyword) && strings.EqualFold(rest[:len(keyword)], keyword) {
+				length = len(keyword)
+				break
+			}
+		}
+		if length == 0 {
+			_, length = utf8.DecodeRuneInString(rest)
+		}
+
+		tokens = append(tokens, token{kind: tokenWord, text: rest[:length], offset: offset + start})
+		start += length
+	}
+
+	return tokens
+}
+
+// isWord returns true if the token is the word `word` (case insensitive).
+func (t token) isWord(word string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+// isLetter returns true if the token is a single letter.
+func (t token) isLetter() bool {
+	return t.kind == tokenWord && utf8.RuneCountInString(t.text) == 1
+}
+
+type patternParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *patternParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *patternParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+// fail returns the SessionParseError about the token.
+func (p *patternParser) fail(t token, reason string) domain.SessionParseError {
+	return domain.SessionParseError{Offset: t.offset, Token: t.text, Reason: reason}
+}
+
+// number Parse a number no larger than maxPatternNumber.
+func (p *patternParser) number(what string) (int64, error) {
+	t := p.next()
+	if t.kind != tokenNumber {
+		return 0, p.fail(t, "expected "+what)
+	}
+
+	n, err := strconv.ParseInt(t.text, 10, 64)
+	if err != nil || n > maxPatternNumber {
+		return 0, p.fail(t, "the number is too large")
+	}
+	return n, nil
+}
+
+// duration Parse a duration, returning it in seconds.
+func (p *patternParser) duration(what string) (int64, error) {
+	if p.peek().kind != tokenNumber {
+		return 0, p.fail(p.peek(), "expected "+what)
+	}
+
+	var total int64
+	lastUnit := ""
+	for p.peek().kind == tokenNumber {
+		n, err := p.number(what)
+		if err != nil {
+			return 0, err
+		}
+
+		unit := p.peek()
+		if _, ok := unitSeconds[strings.ToLower(unit.text)]; ok && unit.kind == tokenWord {
+			p.next()
+			if lastUnit != "" && unitSeconds[strings.ToLower(unit.text)] >= unitSeconds[lastUnit] {
+				return 0, p.fail(unit, "the units of a duration should go from hours to seconds")
+			}
+			lastUnit = strings.ToLower(unit.text)
+			total += n * unitSeconds[lastUnit]
+			continue
+		}
+
+		// A number without unit ends the duration.
+		switch lastUnit {
+		case "":
+			total += n * 60
+		case "h":
+			total += n * 60
+		case "m", "min":
+			total += n
+		default:
+			return 0, p.fail(p.tokens[p.pos-1], "a duration cannot go below seconds")
+		}
+		break
+	}
+
+	return total, nil
+}
+
+// ParseSession Parse a session pattern (see the grammar above) into a
+// session. The error is a domain.SessionParseError, which tells where the
+// pattern went wrong.
+func ParseSession(text string) (domain.SessionDefaultData, error) {
+	var sessionData domain.SessionDefaultData
+
+	tokens, err := tokenize(text)
+	if err != nil {
+		return sessionData, err
+	}
+	p := &patternParser{tokens: tokens}
+
+	if p.peek().kind == tokenSlash {
+		p.next()
+	}
+
+	first := p.peek()
+	sprint, err := p.duration("the duration of the pomodoros")
+	if err != nil {
+		return sessionData, err
+	}
+	if sprint <= 0 {
+		return sessionData, p.fail(first, "the pomodoros should last more than 0 seconds")
+	}
+	sessionData.PomodoroDurationSet = domain.PomodoroDuration(sprint)
+	sessionData.SprintDurationSet = 1
+
+	restSet := false
+	if p.peek().kind == tokenSlash {
+		p.next()
+		rest, err := p.duration("the duration of the rest")
+		if err != nil {
+			return sessionData, err
+		}
+		sessionData.RestDurationSet = domain.RestDuration(rest)
+		restSet = true
+	}
+
+	countSet, longSet := false, false
+	for p.peek().kind != tokenEnd {
+		keyword := p.next()
+		switch {
+		case keyword.isWord("for") || keyword.isWord("x"):
+			if countSet {
+				return sessionData, p.fail(keyword, "the number of pomodoros is given twice")
+			}
+			countSet = true
+
+			if p.peek().isLetter() {
+				p.next()
+				sessionData.SprintDurationSet = domain.UnspecifiedSprintCardinality
+				continue
+			}
+			countToken := p.peek()
+			count, err := p.number("the number of pomodoros (or X)")
+			if err != nil {
+				return sessionData, err
+			}
+			if count <= 0 {
+				return sessionData, p.fail(countToken, "there should be at least a pomodoro")
+			}
+			sessionData.SprintDurationSet = domain.SprintDuration(count)
+		case keyword.isWord("rest"):
+			if restSet {
+				return sessionData, p.fail(keyword, "the rest is given twice")
+			}
+			restSet = true
+
+			rest, err := p.duration("the duration of the rest")
+			if err != nil {
+				return sessionData, err
+			}
+			sessionData.RestDurationSet = domain.RestDuration(rest)
+		case keyword.isWord("long"):
+			if longSet {
+				return sessionData, p.fail(keyword, "the long rest is given twice")
+			}
+			longSet = true
+
+			longToken := p.peek()
+			longRest, err := p.duration("the duration of the long rest")
+			if err != nil {
+				return sessionData, err
+			}
+			if longRest <= 0 {
+				return sessionData, p.fail(longToken, "the long rest should last more than 0 seconds")
+			}
+			sessionData.LongRestDurationSet = domain.RestDuration(longRest)
+
+			// A long rest every 4 sprints, as in the classic technique, unless
+			// the user specified otherwise.
+			sessionData.LongRestInterval = domain.DefaultLongRestInterval
+			if p.peek().isWord("every") {
+				p.next()
+				intervalToken := p.peek()
+				interval, err := p.number("the number of pomodoros between long rests")
+				if err != nil {
+					return sessionData, err
+				}
+				if interval <= 0 {
+					return sessionData, p.fail(intervalToken, "long rests should come every 1 pomodoro or more")
+				}
+				sessionData.LongRestInterval = domain.SprintDuration(interval)
+			}
+		default:
+			return sessionData, p.fail(keyword, "expected for, x, rest or long")
+		}
+	}
+
+	if countSet && !restSet {
+		// Default 5 minutes of rest duration in case user did not specify.
+		sessionData.RestDurationSet = domain.DefaultRestTime
+	}
+
+	return sessionData, nil
+}
+
+// IsSessionPattern returns true if the command looks like a session pattern
+// (a slash followed by a digit), whether it is a valid one or not.
+func IsSessionPattern(command string) bool {
+	return len(command) > 1 && command[0] == '/' && command[1] >= '0' && command[1] <= '9'
+}
@@ -0,0 +1,149 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package inputprocess
+
+import (
+	"GoforPomodoro/internal/domain"
+	"errors"
+	"testing"
+)
+
+func TestParseSession(t *testing.T) {
+	const x = domain.UnspecifiedSprintCardinality
+	const m = 60
+
+	tests := []struct {
+		pattern  string
+		expected domain.SessionDefaultData
+	}{
+		// The original syntax
+		{"/25", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 25 * m}},
+		{"/30for4", domain.SessionDefaultData{SprintDurationSet: 4, PomodoroDurationSet: 30 * m, RestDurationSet: 5 * m}},
+		{"/25for4rest5", domain.SessionDefaultData{SprintDurationSet: 4, PomodoroDurationSet: 25 * m, RestDurationSet: 5 * m}},
+		{"/30forXrest7", domain.SessionDefaultData{SprintDurationSet: x, PomodoroDurationSet: 30 * m, RestDurationSet: 7 * m}},
+		{"/25for8rest5long20every4", domain.SessionDefaultData{SprintDurationSet: 8, PomodoroDurationSet: 25 * m,
+			RestDurationSet: 5 * m, LongRestDurationSet: 20 * m, LongRestInterval: 4}},
+		{"/25for8rest5long20", domain.SessionDefaultData{SprintDurationSet: 8, PomodoroDurationSet: 25 * m,
+			RestDurationSet: 5 * m, LongRestDurationSet: 20 * m, LongRestInterval: domain.DefaultLongRestInterval}},
+		{"/25for8long20", domain.SessionDefaultData{SprintDurationSet: 8, PomodoroDurationSet: 25 * m,
+			RestDurationSet: 5 * m, LongRestDurationSet: 20 * m, LongRestInterval: domain.DefaultLongRestInterval}},
+		{"/25for8long20every4", domain.SessionDefaultData{SprintDurationSet: 8, PomodoroDurationSet: 25 * m,
+			RestDurationSet: 5 * m, LongRestDurationSet: 20 * m, LongRestInterval: 4}},
+		{"/25for6rest5long20every3", domain.SessionDefaultData{SprintDurationSet: 6, PomodoroDurationSet: 25 * m,
+			RestDurationSet: 5 * m, LongRestDurationSet: 20 * m, LongRestInterval: 3}},
+		{"/25forXrest5long20every4", domain.SessionDefaultData{SprintDurationSet: x, PomodoroDurationSet: 25 * m,
+			RestDurationSet: 5 * m, LongRestDurationSet: 20 * m, LongRestInterval: 4}},
+
+		// Units, spaces and the other syntaxes
+		{"/1h30", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 90 * m}},
+		{"/1h 30m", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 90 * m}},
+		{"/2h", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 120 * m}},
+		{"/45s", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 45}},
+		{"/1m30", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 90}},
+		{"/25min", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 25 * m}},
+		{"/90m x2 rest 15m", domain.SessionDefaultData{SprintDurationSet: 2, PomodoroDurationSet: 90 * m, RestDurationSet: 15 * m}},
+		{"/25m/5m x4", domain.SessionDefaultData{SprintDurationSet: 4, PomodoroDurationSet: 25 * m, RestDurationSet: 5 * m}},
+		{"/25/5", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 25 * m, RestDurationSet: 5 * m}},
+		{"/50 X4 REST 10 LONG 30 EVERY 2", domain.SessionDefaultData{SprintDurationSet: 4, PomodoroDurationSet: 50 * m,
+			RestDurationSet: 10 * m, LongRestDurationSet: 30 * m, LongRestInterval: 2}},
+		{"/25 x x", domain.SessionDefaultData{SprintDurationSet: x, PomodoroDurationSet: 25 * m, RestDurationSet: 5 * m}},
+		{"25 rest 0", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 25 * m}},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			sessionData, err := ParseSession(test.pattern)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sessionData.String() != test.expected.String() ||
+				sessionData.LongRestInterval != test.expected.LongRestInterval {
+				t.Fatalf("expected %+v, got %+v", test.expected, sessionData)
+			}
+		})
+	}
+}
+
+func TestParseSessionErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		offset  int
+		token   string
+	}{
+		{"", 0, ""},
+		{"/", 1, ""},
+		{"/deep", 1, "d"},
+		{"/0", 1, "0"},
+		{"/25for", 6, ""},
+		{"/25for0", 6, "0"},
+		{"/25forever", 7, "v"},
+		{"/25 hello", 5, "e"},
+		{"/25m 5m", 6, "m"},
+		{"/30m1h", 5, "h"},
+		{"/1s30", 3, "30"},
+		{"/25 x2 x3", 7, "x"},
+		{"/25 rest 5 rest 5", 11, "rest"},
+		{"/25 long 0", 9, "0"},
+		{"/25 long 20 every 0", 18, "0"},
+		{"/999999", 1, "999999"},
+		{"/25 + 5", 4, "+"},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			_, err := ParseSession(test.pattern)
+			var parseErr domain.SessionParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected a SessionParseError, got %v", err)
+			}
+			if parseErr.Offset != test.offset || parseErr.Token != test.token {
+				t.Fatalf("expected the error at %q (%d), got %q (%d): %v",
+					test.token, test.offset, parseErr.Token, parseErr.Offset, err)
+			}
+		})
+	}
+}
+
+func FuzzParseSession(f *testing.F) {
+	for _, seed := range []string{
+		"/25for4rest5", "/30forXrest7", "/25for8rest5long20every4",
+		"/1h30", "/90m x2 rest 15m", "/25m/5m x4", "/45s", "/25forever",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, pattern string) {
+		sessionData, err := ParseSession(pattern)
+		if err != nil {
+			var parseErr domain.SessionParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("%q: expected a SessionParseError, got %v", pattern, err)
+			}
+			if parseErr.Offset < 0 || parseErr.Offset > len(pattern) {
+				t.Fatalf("%q: error offset %d out of the pattern", pattern, parseErr.Offset)
+			}
+			return
+		}
+
+		if sessionData.PomodoroDurationSet <= 0 || sessionData.RestDurationSet < 0 {
+			t.Fatalf("%q: invalid durations %+v", pattern, sessionData)
+		}
+		if sessionData.SprintDurationSet <= 0 && sessionData.SprintDurationSet != domain.UnspecifiedSprintCardinality {
+			t.Fatalf("%q: invalid number of sprints %d", pattern, sessionData.SprintDurationSet)
+		}
+		if sessionData.HasLongRest() != (sessionData.LongRestDurationSet > 0) {
+			t.Fatalf("%q: long rest without interval %+v", pattern, sessionData)
+		}
+	})
+}
@@ -24,14 +24,6 @@ import (
 	"strings"
 )
 
-const BasicPattern = `\/([1-9]\d*)(for([A-Z]|([1-9]\d*))(rest([1-9]\d*))?)?` // `\/([1-9]\d*)`
-
-const (
-	MinutesGroup     = 1
-	CardinalityGroup = 3
-	RestGroup        = 6
-)
-
 var privacySettingsCommands = "/accept_all::/accept_essential"
 
 func IsPrivacySettingsCommand(text string) bool {
@@ -59,70 +51,117 @@ func ParametersFrom(text string) []string {
 	return strings.Split(text, " ")[1:]
 }
 
-func ValidateSessionParsed(sessionData domain.SessionDefaultData) (domain.SessionDefaultData, error) {
-	// The maximum time for a session shall not exceed 48 hours
-	var limit int64 = 48 * 60 * 60
+// ValidateSessionParsed Check the session against the limits. The error is a
+// domain.SessionLimitExceeded naming the first limit the session exceeds.
+func ValidateSessionParsed(
+	sessionData domain.SessionDefaultData,
+	limits domain.SessionLimits,
+) (domain.SessionDefaultData, error) {
+	exceeded := func(limit domain.SessionLimit, value int64) error {
+		return domain.SessionLimitExceeded{Limit: limit, Value: value, Bound: int64(limits.Get(limit))}
+	}
+	minutes := func(seconds int64) int64 {
+		return (seconds + 59) / 60
+	}
 
-	sessionTime := sessionData.CalculateSessionTimeInSeconds()
+	unspecified := sessionData.SprintDurationSet <= domain.UnspecifiedSprintCardinality
+	if !unspecified && limits.MaxSprints > 0 && int(sessionData.SprintDurationSet) > limits.MaxSprints {
+		return sessionData, exceeded(domain.LimitMaxSprints, int64(sessionData.SprintDurationSet))
+	}
 
-	if sessionTime > limit {
-		return sessionData, errors.New("this session lasts too long")
-	} else {
-		return sessionData, nil
+	sprints := []int64{int64(sessionData.PomodoroDurationSet)}
+	for _, step := range sessionData.Plan {
+		sprints = append(sprints, int64(step.PomodoroDuration))
+	}
+	for _, sprint := range sprints {
+		if limits.MaxSprintMinutes > 0 && sprint > int64(limits.MaxSprintMinutes)*60 {
+			return sessionData, exceeded(domain.LimitMaxSprint, minutes(sprint))
+		}
+		if sprint < int64(limits.MinSprintSeconds) {
+			// The session is too short: it "exceeds" the lower bound.
+			return sessionData, exceeded(domain.LimitMinSprint, sprint)
+		}
 	}
-}
 
-func ParsePatternToSession(r *regexp.Regexp, text string) utils.Optional[domain.SessionDefaultData] {
-	if r == nil {
-		r = regexp.MustCompile(BasicPattern)
+	if unspecified {
+		if limits.MaxUnspecifiedMinutes > 0 && int64(sessionData.PomodoroDurationSet) > int64(limits.MaxUnspecifiedMinutes)*60 {
+			return sessionData, exceeded(domain.LimitMaxUnspecified, minutes(int64(sessionData.PomodoroDurationSet)))
+		}
+		return sessionData, nil
 	}
-	matches := r.FindAllStringSubmatch(text, -1)
 
-	var sessionDefaultData domain.SessionDefaultData
+	sessionTime := sessionData.CalculateSessionTimeInSeconds()
+	if limits.MaxTotalMinutes > 0 && sessionTime > int64(limits.MaxTotalMinutes)*60 {
+		return sessionData, exceeded(domain.LimitMaxTotal, minutes(sessionTime))
+	}
 
-	match := false
-	for _, v := range matches {
-		match = true
+	return sessionData, nil
+}
 
-		sessionDefaultData.SprintDurationSet = 1
+// ParsePlanToSession Parse the parameters of the /plan command, e.g.
+// ["50/10", "50/10", "25/5", "25/0"], into a session that runs each step
+// with its own sprint and rest duration (in minutes).
+func ParsePlanToSession(parameters []string) (domain.SessionDefaultData, error) {
+	var plan domain.SessionPlan
 
-		// Mandatory parameter for this command.
-		pomDuration, err := strconv.Atoi(v[MinutesGroup])
-		if err != nil {
-			return utils.OptionalOfNil[domain.SessionDefaultData]()
+	for _, parameter := range parameters {
+		if parameter == "" {
+			continue
 		}
-		sessionDefaultData.PomodoroDurationSet = domain.PomodoroDuration(pomDuration * 60) // time from minutes to seconds.
-
-		// Other parameters are optional
-		cardinality := v[CardinalityGroup]
-		if utils.IsCapitalizedLetterStr(cardinality) {
-			// A capitalized letter was provided
-			sessionDefaultData.SprintDurationSet = domain.UnspecifiedSprintCardinality
-
-			// Default 5 minutes of rest duration in case user did not specify.
-			sessionDefaultData.RestDurationSet = domain.DefaultRestTime
-		} else {
-			// A number or else was provided
-			sprintDuration, err := strconv.Atoi(v[CardinalityGroup])
-			if err == nil {
-				sessionDefaultData.SprintDurationSet = domain.SprintDuration(sprintDuration)
-
-				// Default 5 minutes of rest duration in case user did not specify.
-				sessionDefaultData.RestDurationSet = domain.DefaultRestTime
-			}
+
+		durations := strings.Split(parameter, "/")
+		if len(durations) != 2 {
+			return domain.SessionDefaultData{}, errors.New("a plan step should be written as sprint/rest")
 		}
 
-		restDuration, err := strconv.Atoi(v[RestGroup])
-		if err == nil {
-			sessionDefaultData.RestDurationSet = domain.RestDuration(restDuration * 60)
+		pomDuration, err := strconv.Atoi(durations[0])
+		if err != nil || pomDuration <= 0 {
+			return domain.SessionDefaultData{}, errors.New("the sprint of a plan step should last at least a minute")
+		}
+		restDuration, err := strconv.Atoi(durations[1])
+		if err != nil || restDuration < 0 {
+			return domain.SessionDefaultData{}, errors.New("the rest of a plan step cannot be negative")
 		}
 
-		break
+		plan = append(plan, domain.PlanStep{
+			PomodoroDuration: domain.PomodoroDuration(pomDuration * 60), // time from minutes to seconds.
+			RestDuration:     domain.RestDuration(restDuration * 60),
+		})
 	}
 
-	if !match {
-		return utils.OptionalOfNil[domain.SessionDefaultData]()
+	if plan.IsZero() {
+		return domain.SessionDefaultData{}, errors.New("the plan has no steps")
+	}
+
+	return plan.ToDefaultData(), nil
+}
+
+// ParsePresetPattern Parse the session saved in a preset: either a pattern
+// like "50for3rest10" or "90m x2 rest 15m" (with or without the leading
+// slash, see ParseSession) or a plan like "plan 50/10 25/5".
+func ParsePresetPattern(pattern string) (domain.SessionDefaultData, error) {
+	fields := strings.Fields(pattern)
+	if len(fields) == 0 {
+		return domain.SessionDefaultData{}, errors.New("the preset is empty")
+	}
+
+	if strings.TrimPrefix(fields[0], "/") == "plan" {
+		return ParsePlanToSession(fields[1:])
+	}
+
+	return ParseSession(pattern)
+}
+
+var timeOfDayRegexp = regexp.MustCompile(`^([01]?\d|2[0-3])[:.]([0-5]\d)$`)
+
+// ParseTimeOfDay Parse a time of the day written as HH:MM (24-hour clock).
+func ParseTimeOfDay(text string) (hour int, minute int, err error) {
+	matches := timeOfDayRegexp.FindStringSubmatch(text)
+	if matches == nil {
+		return 0, 0, errors.New("the time should be written as HH:MM, e.g. 09:00")
 	}
 
-	return utils.OptionalOf(sessionDefaultData)
+	hour, _ = strconv.Atoi(matches[1])
+	minute, _ = strconv.Atoi(matches[2])
+	return hour, minute, nil
 }
@@ -0,0 +1,119 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package inputprocess
+
+import (
+	"GoforPomodoro/internal/domain"
+	"testing"
+)
+
+func TestValidateSessionParsed(t *testing.T) {
+	limits := domain.DefaultSessionLimits
+	limits.MaxSprintMinutes = 90
+
+	tests := []struct {
+		session string
+		limit   domain.SessionLimit // "" if the session is valid
+	}{
+		{"25for4rest5", ""},
+		{"90", ""},
+		{"91", domain.LimitMaxSprint},
+		{"5s", domain.LimitMinSprint},
+		{"25for101", domain.LimitMaxSprints},
+		{"90for40rest30", domain.LimitMaxTotal},
+		{"90forxrest5", ""},
+		{"plan 50/10 100/0", domain.LimitMaxSprint},
+	}
+	for _, test := range tests {
+		sessionData, err := ParsePresetPattern(test.session)
+		if err != nil {
+			t.Fatalf("%q: unexpected parsing error: %v", test.session, err)
+		}
+		_, err = ValidateSessionParsed(sessionData, limits)
+		if test.limit == "" {
+			if err != nil {
+				t.Fatalf("%q: unexpected error: %v", test.session, err)
+			}
+			continue
+		}
+		exceeded, ok := err.(domain.SessionLimitExceeded)
+		if !ok || exceeded.Limit != test.limit {
+			t.Fatalf("%q: expected the %q limit to be exceeded, got %v", test.session, test.limit, err)
+		}
+	}
+
+	limits.MaxUnspecifiedMinutes = 60
+	sessionData, _ := ParsePresetPattern("90forx")
+	if _, err := ValidateSessionParsed(sessionData, limits); err == nil {
+		t.Fatalf("the sprint of a session with no set number of sprints should not exceed its limit")
+	}
+}
+
+func TestParsePlanToSession(t *testing.T) {
+	sessionData, err := ParsePlanToSession([]string{"50/10", "50/10", "25/5", "25/0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sessionData.SprintDurationSet != 4 {
+		t.Fatalf("expected 4 sprints, got %d", sessionData.SprintDurationSet)
+	}
+	if sessionData.Plan.Step(2).PomodoroDuration != 25*60 || sessionData.Plan.Step(2).RestDuration != 5*60 {
+		t.Fatalf("unexpected third step %+v", sessionData.Plan.Step(2))
+	}
+	if total := sessionData.CalculateSessionTimeInSeconds(); total != (50+10+50+10+25+5+25)*60 {
+		t.Fatalf("unexpected session time %d", total)
+	}
+
+	for _, parameters := range [][]string{{}, {"50"}, {"0/10"}, {"50/-1"}, {"50/10/5"}, {"a/b"}} {
+		if _, err := ParsePlanToSession(parameters); err == nil {
+			t.Fatalf("plan %v should not be valid", parameters)
+		}
+	}
+}
+
+func TestParsePresetPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected domain.SessionDefaultData
+	}{
+		{"50for3rest10", domain.SessionDefaultData{SprintDurationSet: 3, PomodoroDurationSet: 50 * 60, RestDurationSet: 10 * 60}},
+		{"/25for4", domain.SessionDefaultData{SprintDurationSet: 4, PomodoroDurationSet: 25 * 60, RestDurationSet: domain.DefaultRestTime}},
+		{"25", domain.SessionDefaultData{SprintDurationSet: 1, PomodoroDurationSet: 25 * 60}},
+	}
+	for _, test := range tests {
+		sessionData, err := ParsePresetPattern(test.pattern)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.pattern, err)
+		}
+		if sessionData.SprintDurationSet != test.expected.SprintDurationSet ||
+			sessionData.PomodoroDurationSet != test.expected.PomodoroDurationSet ||
+			sessionData.RestDurationSet != test.expected.RestDurationSet {
+			t.Fatalf("%q: expected %+v, got %+v", test.pattern, test.expected, sessionData)
+		}
+	}
+
+	sessionData, err := ParsePresetPattern("plan 50/10 25/0")
+	if err != nil || len(sessionData.Plan) != 2 {
+		t.Fatalf("plan preset should be parsed, got %+v (%v)", sessionData.Plan, err)
+	}
+
+	for _, pattern := range []string{"", "deep", "50for3 extra", "50forever"} {
+		if _, err := ParsePresetPattern(pattern); err == nil {
+			t.Fatalf("%q should not be a valid preset", pattern)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package messenger
+
+import (
+	"GoforPomodoro/internal/domain"
+)
+
+// Messenger is the way the bot talks to its chats.
+//
+// Telegram is the Messenger of the bot; Recorder keeps in memory what the bot
+// says, e.g. for the tests.
+type Messenger interface {
+	// SendText sends a plain text message to the chat, returning its ID.
+	SendText(chatId domain.ChatID, text string) (int, error)
+
+	// SendHTML sends a message formatted in HTML, with no link previews.
+	SendHTML(chatId domain.ChatID, text string) (int, error)
+
+	// SendWithKeyboard sends a plain text message with buttons under it.
+	SendWithKeyboard(chatId domain.ChatID, text string, keyboard Keyboard) (int, error)
+
+	// EditText replaces the text of a message sent by the bot.
+	EditText(chatId domain.ChatID, messageId int, text string) error
+
+	// EditKeyboard replaces the buttons of a message sent by the bot.
+	EditKeyboard(chatId domain.ChatID, messageId int, keyboard Keyboard) error
+
+	// AnswerCallback answers the press of a button, showing the text (if not
+	// empty) to the user.
+	AnswerCallback(callbackId string, text string) error
+
+	// Username returns the username of the user.
+	Username(userId domain.ChatID) (string, error)
+
+	// SetCommands publishes the command list the apps of the users suggest.
+	SetCommands(list CommandList) error
+}
+
+// UpdateSource is where the bot gets the messages and the button presses of
+// the users from.
+type UpdateSource interface {
+	// Updates returns the channel of the updates. It is closed when there
+	// are no more updates.
+	Updates() <-chan Update
+}
+
+// Update is a message to the bot or the press of one of its buttons: only one
+// of the fields is set.
+type Update struct {
+	Message  *Message
+	Callback *Callback
+}
+
+// Message is a text message sent to the bot.
+type Message struct {
+	ChatID   domain.ChatID
+	SenderID domain.ChatID
+
+	// SenderUsername is empty if the sender has no username.
+	SenderUsername string
+
+	// SenderLanguage is the language of the app of the sender (e.g. "it-IT"),
+	// if known.
+	SenderLanguage string
+
+	Text    string
+	IsGroup bool
+}
+
+// Callback is the press of a button of a message of the bot.
+type Callback struct {
+	ID        string
+	ChatID    domain.ChatID
+	SenderID  domain.ChatID
+	MessageID int
+
+	// Data is the Button.Data of the button pressed.
+	Data string
+
+	// Keyboard is the current keyboard of the message.
+	Keyboard Keyboard
+}
+
+// Button is a button under a message. Data is sent back to the bot in a
+// Callback when the button is pressed.
+type Button struct {
+	Text string
+	Data string
+}
+
+// Keyboard is the rows of buttons under a message.
+type Keyboard [][]Button
+
+// NewKeyboard returns a keyboard with the given rows.
+func NewKeyboard(rows ...[]Button) Keyboard {
+	return rows
+}
+
+// NewRow returns a row of buttons.
+func NewRow(buttons ...Button) []Button {
+	return buttons
+}
+
+// Equal returns true if the two keyboards have the same buttons.
+func (k Keyboard) Equal(other Keyboard) bool {
+	if len(k) != len(other) {
+		return false
+	}
+	for i := range k {
+		if len(k[i]) != len(other[i]) {
+			return false
+		}
+		for j := range k[i] {
+			if k[i][j] != other[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CommandChats tells to which chats a command list applies.
+type CommandChats int
+
+const (
+	PrivateChats CommandChats = iota
+	GroupChats
+	// SingleChat is the chat of CommandList.ChatID.
+	SingleChat
+)
+
+// CommandList is the list of commands suggested in some chats, to the users
+// of a language.
+type CommandList struct {
+	Chats  CommandChats
+	ChatID domain.ChatID
+
+	// Language is the code of the language of the users (e.g. "it"), empty
+	// for all the users whose language has no list.
+	Language string
+
+	Commands []CommandInfo
+}
+
+// CommandInfo is a command in a CommandList.
+type CommandInfo struct {
+	// Name is the command without the slash, e.g. "pause".
+	Name        string
+	Description string
+}
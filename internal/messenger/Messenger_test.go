@@ -0,0 +1,130 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package messenger
+
+import (
+	"GoforPomodoro/internal/domain"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	keyboard := NewKeyboard(NewRow(Button{Text: "⏸", Data: "act:pause"}))
+
+	first, _ := recorder.SendText(1, "hello")
+	second, _ := recorder.SendWithKeyboard(1, "session", keyboard)
+	_, _ = recorder.SendHTML(2, "<b>other chat</b>")
+
+	if err := recorder.EditText(1, first, "hello again"); err != nil {
+		t.Fatalf("EditText: %v", err)
+	}
+	if err := recorder.EditKeyboard(1, second, nil); err != nil {
+		t.Fatalf("EditKeyboard: %v", err)
+	}
+	if err := recorder.EditText(2, first, "wrong chat"); err == nil {
+		t.Errorf("editing the message of another chat should fail")
+	}
+
+	messages := recorder.Messages(1)
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages in chat 1, got %+v", messages)
+	}
+	if messages[0].Text != "hello again" || messages[0].Edits != 1 {
+		t.Errorf("text not edited: %+v", messages[0])
+	}
+	if messages[1].Keyboard != nil || messages[1].Edits != 1 {
+		t.Errorf("keyboard not edited: %+v", messages[1])
+	}
+	if other := recorder.Messages(2); len(other) != 1 || !other[0].HTML {
+		t.Errorf("HTML message not recorded: %+v", other)
+	}
+
+	if _, err := recorder.Username(7); err == nil {
+		t.Errorf("unknown users should not be resolved")
+	}
+	recorder.SetUsername(7, "seven")
+	if username, err := recorder.Username(7); err != nil || username != "seven" {
+		t.Errorf("Username(7) = %q, %v", username, err)
+	}
+}
+
+func TestKeyboardEqual(t *testing.T) {
+	a := NewKeyboard(NewRow(Button{"⏸", "act:pause"}, Button{"⏹", "act:cancel"}))
+	b := NewKeyboard(NewRow(Button{"⏸", "act:pause"}, Button{"⏹", "act:cancel"}))
+	c := NewKeyboard(NewRow(Button{"⏸", "act:pause"}), NewRow(Button{"⏹", "act:cancel"}))
+
+	if !a.Equal(b) {
+		t.Errorf("%v should equal %v", a, b)
+	}
+	if a.Equal(c) || a.Equal(nil) {
+		t.Errorf("%v should not equal %v", a, c)
+	}
+}
+
+func TestFromTelegramKeyboard(t *testing.T) {
+	keyboard := NewKeyboard(
+		NewRow(Button{"▶", "act:next"}),
+		NewRow(Button{"⏸", "act:pause"}, Button{"⏹", "act:cancel"}),
+	)
+	if got := fromTelegramKeyboard(toTelegramKeyboard(keyboard)); !got.Equal(keyboard) {
+		t.Errorf("keyboard changed in the conversion: %v", got)
+	}
+}
+
+func TestFromTelegramUpdate(t *testing.T) {
+	message := tgbotapi.Update{Message: &tgbotapi.Message{
+		From: &tgbotapi.User{ID: 42, UserName: "user", LanguageCode: "it-IT"},
+		Chat: &tgbotapi.Chat{ID: -100, Type: "supergroup"},
+		Text: "/pause",
+	}}
+	update, ok := fromTelegramUpdate(message)
+	if !ok || update.Message == nil {
+		t.Fatalf("message not converted")
+	}
+	expected := Message{ChatID: -100, SenderID: 42, SenderUsername: "user", SenderLanguage: "it-IT", Text: "/pause", IsGroup: true}
+	if *update.Message != expected {
+		t.Errorf("message converted to %+v", *update.Message)
+	}
+
+	data := "act:pause"
+	query := tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:   "query",
+		From: &tgbotapi.User{ID: 42},
+		Data: data,
+		Message: &tgbotapi.Message{
+			MessageID: 7,
+			Chat:      &tgbotapi.Chat{ID: 42, Type: "private"},
+			ReplyMarkup: &tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{
+				{{Text: "⏸", CallbackData: &data}},
+			}},
+		},
+	}}
+	update, ok = fromTelegramUpdate(query)
+	if !ok || update.Callback == nil {
+		t.Fatalf("callback query not converted")
+	}
+	callback := update.Callback
+	if callback.ChatID != domain.ChatID(42) || callback.MessageID != 7 || callback.Data != data ||
+		!callback.Keyboard.Equal(NewKeyboard(NewRow(Button{"⏸", data}))) {
+		t.Errorf("callback query converted to %+v", *callback)
+	}
+
+	// Channel posts have no sender.
+	if _, ok := fromTelegramUpdate(tgbotapi.Update{ChannelPost: &tgbotapi.Message{Text: "/pause"}}); ok {
+		t.Errorf("channel posts should be ignored")
+	}
+}
@@ -0,0 +1,194 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package messenger
+
+import (
+	"GoforPomodoro/internal/domain"
+	"fmt"
+	"sync"
+)
+
+// Recorder is a Messenger that keeps in memory the messages of the bot,
+// instead of sending them. It is also the UpdateSource of the updates given
+// to Push.
+//
+// It is safe for concurrent use.
+type Recorder struct {
+	lock sync.Mutex
+
+	messages  []RecordedMessage
+	answers   []RecordedAnswer
+	commands  []CommandList
+	usernames map[domain.ChatID]string
+
+	updates chan Update
+}
+
+// RecordedMessage is a message sent by the bot, with the edits it received.
+type RecordedMessage struct {
+	ChatID    domain.ChatID
+	MessageID int
+	Text      string
+	HTML      bool
+	Keyboard  Keyboard
+
+	// Edits counts how many times the message was edited.
+	Edits int
+}
+
+// RecordedAnswer is the answer of the bot to the press of a button.
+type RecordedAnswer struct {
+	CallbackID string
+	Text       string
+}
+
+var _ Messenger = &Recorder{}
+var _ UpdateSource = &Recorder{}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		usernames: make(map[domain.ChatID]string),
+		updates:   make(chan Update),
+	}
+}
+
+func (r *Recorder) record(message RecordedMessage) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	message.MessageID = len(r.messages) + 1
+	r.messages = append(r.messages, message)
+	return message.MessageID, nil
+}
+
+func (r *Recorder) SendText(chatId domain.ChatID, text string) (int, error) {
+	return r.record(RecordedMessage{ChatID: chatId, Text: text})
+}
+
+func (r *Recorder) SendHTML(chatId domain.ChatID, text string) (int, error) {
+	return r.record(RecordedMessage{ChatID: chatId, Text: text, HTML: true})
+}
+
+func (r *Recorder) SendWithKeyboard(chatId domain.ChatID, text string, keyboard Keyboard) (int, error) {
+	return r.record(RecordedMessage{ChatID: chatId, Text: text, Keyboard: keyboard})
+}
+
+// edit Apply the change to the message, if the bot sent it to the chat.
+func (r *Recorder) edit(chatId domain.ChatID, messageId int, change func(message *RecordedMessage)) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if messageId < 1 || messageId > len(r.messages) || r.messages[messageId-1].ChatID != chatId {
+		return fmt.Errorf("message %d not found in chat %d", messageId, chatId)
+	}
+	message := &r.messages[messageId-1]
+	change(message)
+	message.Edits++
+	return nil
+}
+
+func (r *Recorder) EditText(chatId domain.ChatID, messageId int, text string) error {
+	return r.edit(chatId, messageId, func(message *RecordedMessage) {
+		message.Text = text
+	})
+}
+
+func (r *Recorder) EditKeyboard(chatId domain.ChatID, messageId int, keyboard Keyboard) error {
+	return r.edit(chatId, messageId, func(message *RecordedMessage) {
+		message.Keyboard = keyboard
+	})
+}
+
+func (r *Recorder) AnswerCallback(callbackId string, text string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.answers = append(r.answers, RecordedAnswer{CallbackID: callbackId, Text: text})
+	return nil
+}
+
+func (r *Recorder) Username(userId domain.ChatID) (string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	username, ok := r.usernames[userId]
+	if !ok {
+		return "", fmt.Errorf("user %d not found", userId)
+	}
+	return username, nil
+}
+
+func (r *Recorder) SetCommands(list CommandList) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.commands = append(r.commands, list)
+	return nil
+}
+
+// SetUsername Make the user known to Username.
+func (r *Recorder) SetUsername(userId domain.ChatID, username string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.usernames[userId] = username
+}
+
+// Messages returns the messages sent to the chat, in the order they were
+// sent.
+func (r *Recorder) Messages(chatId domain.ChatID) []RecordedMessage {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var messages []RecordedMessage
+	for _, message := range r.messages {
+		if message.ChatID == chatId {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// Answers returns the answers to the presses of the buttons.
+func (r *Recorder) Answers() []RecordedAnswer {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]RecordedAnswer(nil), r.answers...)
+}
+
+// Commands returns the command lists published.
+func (r *Recorder) Commands() []CommandList {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]CommandList(nil), r.commands...)
+}
+
+func (r *Recorder) Updates() <-chan Update {
+	return r.updates
+}
+
+// Push Give the update to the reader of Updates, waiting for it to be
+// received.
+func (r *Recorder) Push(update Update) {
+	r.updates <- update
+}
+
+// Close End the updates.
+func (r *Recorder) Close() {
+	close(r.updates)
+}
@@ -0,0 +1,205 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package messenger
+
+import (
+	"GoforPomodoro/internal/domain"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log"
+)
+
+// updatesTimeout is the timeout (in seconds) of the long polling of the
+// updates.
+const updatesTimeout = 60
+
+// Telegram is the Messenger of the Telegram bot API. It is also the
+// UpdateSource that polls the updates of the bot.
+type Telegram struct {
+	bot *tgbotapi.BotAPI
+}
+
+var _ Messenger = &Telegram{}
+var _ UpdateSource = &Telegram{}
+
+// NewTelegram connects to the bot with the given token.
+func NewTelegram(token string, debug bool) (*Telegram, error) {
+	bot, err := tgbotapi.NewBotAPI(token)
+	if err != nil {
+		return nil, err
+	}
+	bot.Debug = debug
+
+	return &Telegram{bot: bot}, nil
+}
+
+// UserName returns the username of the bot.
+func (t *Telegram) UserName() string {
+	return t.bot.Self.UserName
+}
+
+func (t *Telegram) send(chattable tgbotapi.Chattable) (int, error) {
+	message, err := t.bot.Send(chattable)
+	if err != nil {
+		return 0, err
+	}
+	return message.MessageID, nil
+}
+
+func (t *Telegram) SendText(chatId domain.ChatID, text string) (int, error) {
+	return t.send(tgbotapi.NewMessage(int64(chatId), text))
+}
+
+func (t *Telegram) SendHTML(chatId domain.ChatID, text string) (int, error) {
+	msg := tgbotapi.NewMessage(int64(chatId), text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.DisableWebPagePreview = true
+	return t.send(msg)
+}
+
+func (t *Telegram) SendWithKeyboard(chatId domain.ChatID, text string, keyboard Keyboard) (int, error) {
+	msg := tgbotapi.NewMessage(int64(chatId), text)
+	msg.ReplyMarkup = toTelegramKeyboard(keyboard)
+	return t.send(msg)
+}
+
+func (t *Telegram) EditText(chatId domain.ChatID, messageId int, text string) error {
+	_, err := t.bot.Send(tgbotapi.NewEditMessageText(int64(chatId), messageId, text))
+	return err
+}
+
+func (t *Telegram) EditKeyboard(chatId domain.ChatID, messageId int, keyboard Keyboard) error {
+	_, err := t.bot.Request(tgbotapi.NewEditMessageReplyMarkup(int64(chatId), messageId, toTelegramKeyboard(keyboard)))
+	return err
+}
+
+func (t *Telegram) AnswerCallback(callbackId string, text string) error {
+	_, err := t.bot.Request(tgbotapi.NewCallback(callbackId, text))
+	return err
+}
+
+func (t *Telegram) Username(userId domain.ChatID) (string, error) {
+	chat, err := t.bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: int64(userId)}})
+	if err != nil {
+		return "", err
+	}
+	return chat.UserName, nil
+}
+
+func (t *Telegram) SetCommands(list CommandList) error {
+	var scope tgbotapi.BotCommandScope
+	switch list.Chats {
+	case PrivateChats:
+		scope = tgbotapi.NewBotCommandScopeAllPrivateChats()
+	case GroupChats:
+		scope = tgbotapi.NewBotCommandScopeAllGroupChats()
+	default:
+		scope = tgbotapi.NewBotCommandScopeChat(int64(list.ChatID))
+	}
+
+	var commands []tgbotapi.BotCommand
+	for _, command := range list.Commands {
+		commands = append(commands, tgbotapi.BotCommand{Command: command.Name, Description: command.Description})
+	}
+
+	_, err := t.bot.Request(tgbotapi.NewSetMyCommandsWithScopeAndLanguage(scope, list.Language, commands...))
+	return err
+}
+
+// Updates polls the updates of the bot (long polling).
+func (t *Telegram) Updates() <-chan Update {
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = updatesTimeout
+
+	// Telegram cannot be polled while a webhook is set, e.g. by an earlier
+	// run in webhook mode.
+	if _, err := t.bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		log.Printf("[Telegram::Updates] error in deleting the webhook: %v\n", err.Error())
+	}
+
+	telegramUpdates := t.bot.GetUpdatesChan(u)
+	updates := make(chan Update)
+	go func() {
+		defer close(updates)
+		for telegramUpdate := range telegramUpdates {
+			if update, ok := fromTelegramUpdate(telegramUpdate); ok {
+				updates <- update
+			}
+		}
+	}()
+	return updates
+}
+
+// fromTelegramUpdate converts the update, returning false if it is neither a
+// message of a user nor the press of a button.
+func fromTelegramUpdate(update tgbotapi.Update) (Update, bool) {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		message := update.Message
+		return Update{Message: &Message{
+			ChatID:         domain.ChatID(message.Chat.ID),
+			SenderID:       domain.ChatID(message.From.ID),
+			SenderUsername: message.From.UserName,
+			SenderLanguage: message.From.LanguageCode,
+			Text:           message.Text,
+			IsGroup:        message.Chat.IsGroup() || message.Chat.IsSuperGroup(),
+		}}, true
+	case update.CallbackQuery != nil:
+		query := update.CallbackQuery
+		callback := &Callback{
+			ID:       query.ID,
+			SenderID: domain.ChatID(query.From.ID),
+			Data:     query.Data,
+		}
+		if query.Message != nil {
+			callback.ChatID = domain.ChatID(query.Message.Chat.ID)
+			callback.MessageID = query.Message.MessageID
+			if query.Message.ReplyMarkup != nil {
+				callback.Keyboard = fromTelegramKeyboard(*query.Message.ReplyMarkup)
+			}
+		}
+		return Update{Callback: callback}, true
+	default:
+		return Update{}, false
+	}
+}
+
+func toTelegramKeyboard(keyboard Keyboard) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, row := range keyboard {
+		var buttons []tgbotapi.InlineKeyboardButton
+		for _, button := range row {
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(button.Text, button.Data))
+		}
+		rows = append(rows, buttons)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func fromTelegramKeyboard(markup tgbotapi.InlineKeyboardMarkup) Keyboard {
+	var keyboard Keyboard
+	for _, row := range markup.InlineKeyboard {
+		var buttons []Button
+		for _, button := range row {
+			data := ""
+			if button.CallbackData != nil {
+				data = *button.CallbackData
+			}
+			buttons = append(buttons, Button{Text: button.Text, Data: data})
+		}
+		keyboard = append(keyboard, buttons)
+	}
+	return keyboard
+}
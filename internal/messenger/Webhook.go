@@ -0,0 +1,128 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package messenger
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// webhookSecretHeader is the header in which Telegram sends back the secret
+// token of the webhook.
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// validWebhookSecret matches the secret tokens Telegram accepts.
+var validWebhookSecret = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// Webhook is the UpdateSource of the updates Telegram sends to the bot over
+// HTTP, as an alternative to polling them. It is the http.Handler of the path
+// of its URL.
+type Webhook struct {
+	telegram *Telegram
+	url      *url.URL
+	secret   string
+	updates  chan Update
+}
+
+var _ UpdateSource = &Webhook{}
+var _ http.Handler = &Webhook{}
+
+// NewWebhook returns the webhook of the bot at the given public (HTTPS) URL.
+// Requests that do not carry the secret token are refused.
+func (t *Telegram) NewWebhook(webhookUrl string, secret string) (*Webhook, error) {
+	parsedUrl, err := url.Parse(webhookUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if parsedUrl.Scheme != "https" || parsedUrl.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q: Telegram requires an https:// URL", webhookUrl)
+	}
+	if !validWebhookSecret.MatchString(secret) {
+		return nil, fmt.Errorf("invalid webhook secret: use 1-256 characters among A-Z, a-z, 0-9, _ and -")
+	}
+
+	return &Webhook{
+		telegram: t,
+		url:      parsedUrl,
+		secret:   secret,
+		updates:  make(chan Update),
+	}, nil
+}
+
+// Path returns the path the webhook must be served at.
+func (w *Webhook) Path() string {
+	if w.url.Path == "" {
+		return "/"
+	}
+	return w.url.Path
+}
+
+// Register Tell Telegram to send the updates of the bot to the webhook
+// (setWebhook), from now on.
+func (w *Webhook) Register() error {
+	// The secret_token parameter is not supported by WebhookConfig.
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("url", w.url.String())
+	params.AddNonEmpty("secret_token", w.secret)
+	if err := params.AddInterface("allowed_updates", []string{"message", "callback_query"}); err != nil {
+		return err
+	}
+
+	_, err := w.telegram.bot.MakeRequest("setWebhook", params)
+	return err
+}
+
+func (w *Webhook) Updates() <-chan Update {
+	return w.updates
+}
+
+// ServeHTTP Receive an update from Telegram and wait for it to be read from
+// Updates, so that Telegram sends the next one only after it is handled.
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	secret := r.Header.Get(webhookSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(w.secret)) != 1 {
+		log.Printf("[Webhook::ServeHTTP] refused request from %s: wrong secret token\n", r.RemoteAddr)
+		http.Error(rw, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var telegramUpdate tgbotapi.Update
+	if err := json.NewDecoder(r.Body).Decode(&telegramUpdate); err != nil {
+		http.Error(rw, "invalid update", http.StatusBadRequest)
+		return
+	}
+
+	if update, ok := fromTelegramUpdate(telegramUpdate); ok {
+		select {
+		case w.updates <- update:
+		case <-r.Context().Done():
+			// Telegram gave up: it will send the update again.
+			return
+		}
+	}
+	rw.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,96 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package messenger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWebhook(t *testing.T) {
+	tests := []struct {
+		url, secret string
+		valid       bool
+	}{
+		{"https://example.org/go4pom/webhook", "s3cr3t_-", true},
+		{"http://example.org/webhook", "s3cr3t", false},
+		{"https:///webhook", "s3cr3t", false},
+		{"https://example.org/webhook", "", false},
+		{"https://example.org/webhook", "not secret!", false},
+	}
+	for _, test := range tests {
+		_, err := (&Telegram{}).NewWebhook(test.url, test.secret)
+		if (err == nil) != test.valid {
+			t.Errorf("NewWebhook(%q, %q): error %v, expected valid: %v", test.url, test.secret, err, test.valid)
+		}
+	}
+
+	webhook, _ := (&Telegram{}).NewWebhook("https://example.org/go4pom/webhook", "s3cr3t")
+	if webhook.Path() != "/go4pom/webhook" {
+		t.Errorf("Path() = %q", webhook.Path())
+	}
+}
+
+func TestWebhookServeHTTP(t *testing.T) {
+	webhook, err := (&Telegram{}).NewWebhook("https://example.org/webhook", "s3cr3t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const body = `{"update_id": 1, "message": {"message_id": 2, "text": "/pause",
+		"from": {"id": 42, "username": "user"}, "chat": {"id": 42, "type": "private"}}}`
+
+	serve := func(method string, secret string, body string) int {
+		request := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+		if secret != "" {
+			request.Header.Set(webhookSecretHeader, secret)
+		}
+		recorder := httptest.NewRecorder()
+		webhook.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	if code := serve(http.MethodPost, "", body); code != http.StatusForbidden {
+		t.Errorf("request with no secret: %d", code)
+	}
+	if code := serve(http.MethodPost, "wrong", body); code != http.StatusForbidden {
+		t.Errorf("request with the wrong secret: %d", code)
+	}
+	if code := serve(http.MethodGet, "s3cr3t", ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET request: %d", code)
+	}
+	if code := serve(http.MethodPost, "s3cr3t", "{"); code != http.StatusBadRequest {
+		t.Errorf("invalid update: %d", code)
+	}
+
+	codes := make(chan int)
+	go func() {
+		codes <- serve(http.MethodPost, "s3cr3t", body)
+	}()
+	select {
+	case update := <-webhook.Updates():
+		if update.Message == nil || update.Message.Text != "/pause" || update.Message.SenderID != 42 {
+			t.Errorf("update received as %+v", update)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("update not received")
+	}
+	if code := <-codes; code != http.StatusOK {
+		t.Errorf("valid update: %d", code)
+	}
+}
@@ -30,29 +30,60 @@ const (
 	PomodoroCanceled
 )
 
+// SessionHandlers are the callbacks through which the timer goroutine of a
+// session notifies its events.
+type SessionHandlers struct {
+	RestBegin     func(id domain.ChatID, session *domain.Session)
+	RestFinished  func(id domain.ChatID, session *domain.Session)
+	SessionEnd    func(id domain.ChatID, session *domain.Session, endKind PomodoroEndKind)
+	SessionPaused func(id domain.ChatID, session *domain.Session)
+	PhaseExtended func(id domain.ChatID, session *domain.Session, err error)
+
+	// SprintRestarted is called once the current sprint is voided and started
+	// over (see RestartSprint).
+	SprintRestarted func(id domain.ChatID, session *domain.Session)
+
+	// InterruptionLogged is called once an interruption of the current sprint
+	// is logged (see InterruptSession), or with the reason why it was not.
+	InterruptionLogged func(id domain.ChatID, session *domain.Session, interruption domain.Interruption, err error)
+
+	// SessionReady is called when a rest is over but the chat wants to
+	// confirm the start of the next sprint (see AdvanceSession).
+	SessionReady func(id domain.ChatID, session *domain.Session)
+
+	// OvertimeBegin is called when the time of a sprint is up, but the
+	// session is in flow mode and keeps going until the user takes a break.
+	OvertimeBegin func(id domain.ChatID, session *domain.Session)
+
+	// PhaseEnding is called once per sprint (or rest), when `left` is
+	// the time left before its end, if the chat asked to be warned.
+	PhaseEnding func(id domain.ChatID, session *domain.Session, left time.Duration)
+
+	// PomodoroCompleted is called once a sprint is completed (not skipped
+	// nor canceled), after RestBegin or SessionEnd, with the progress of the
+	// chat toward its daily goal.
+	PomodoroCompleted func(id domain.ChatID, session *domain.Session, progress domain.GoalProgress)
+}
+
 func StartSession(
 	appState *domain.AppState,
 	userId domain.ChatID,
 	currentSession *domain.Session,
-	restBeginHandler func(id domain.ChatID, session *domain.Session),
-	restFinishedHandler func(id domain.ChatID, session *domain.Session),
-	endSessionHandler func(id domain.ChatID, session *domain.Session, endKind PomodoroEndKind),
-	pauseSessionHandler func(id domain.ChatID, session *domain.Session),
+	handlers SessionHandlers,
 ) error {
 	if currentSession.IsZero() {
 		return errors.New("the session is effectively nil")
 	}
 
-	currentSession.Start()
+	if err := currentSession.Start(); err != nil {
+		return err
+	}
 
 	go SpawnSessionTimer(
 		appState,
 		userId,
 		currentSession,
-		restBeginHandler,
-		restFinishedHandler,
-		endSessionHandler,
-		pauseSessionHandler,
+		handlers,
 	)
 	return nil
 }
@@ -61,95 +92,604 @@ func SpawnSessionTimer(
 	appState *domain.AppState,
 	chatId domain.ChatID,
 	currentSession *domain.Session,
-	restBeginHandler func(id domain.ChatID, session *domain.Session),
-	restFinishedHandler func(id domain.ChatID, session *domain.Session),
-	endSessionHandler func(id domain.ChatID, session *domain.Session, endKind PomodoroEndKind),
-	pauseSessionHandler func(id domain.ChatID, session *domain.Session),
+	handlers SessionHandlers,
 ) {
+	scheduler := appState.Scheduler
+
 	// We update session running because it started (or resumed)
 	data.UpdateUserSessionRunning(appState, chatId)
+	scheduleNextAction(appState, chatId, currentSession)
 mainLoop:
 	for {
-		select {
-		case action, ok := <-currentSession.ReadingActionChannel():
-			if ok {
-				// The event was internal (rest started/finished)
-				if action.RestStarted || action.RestFinished {
-					if action.RestStarted {
-						currentSession.RestStarted()
-						restBeginHandler(chatId, currentSession)
-					}
-					if action.RestFinished {
-						currentSession.RestFinished()
-						restFinishedHandler(chatId, currentSession)
-					}
-					// We update session running because it changed state
-					// (rest started or finished)
-					data.UpdateUserSessionRunning(appState, chatId)
+		action, ok := <-currentSession.ReadingActionChannel()
+		if !ok {
+			currentSession.ActionsChannel = nil
+			log.Println("Session channel is closed. Aborting main loop...")
+			break mainLoop
+		}
+
+		// The event was internal (rest started/finished)
+		if action.RestStarted || action.RestFinished {
+			if action.RestStarted {
+				if currentSession.State() != domain.StateSprint || !currentSession.HasSprintEndTimePassed() {
+					// Stale action: the sprint is not over.
 					continue mainLoop
 				}
-
-				// The event was either external (paused/canceled) or internal (finished)
-				if action.Paused || action.Canceled || action.Finished {
-					if action.Paused {
-						currentSession.Pause()
-						pauseSessionHandler(chatId, currentSession)
-					} else if action.Canceled {
-						currentSession.Cancel()
-						endSessionHandler(chatId, currentSession, PomodoroCanceled)
-					} else if action.Finished {
-						currentSession.SetFinished()
-						endSessionHandler(chatId, currentSession, PomodoroFinished)
-					}
-					// We update session running because it changed state
-					// (paused, canceled or finished)
-					data.UpdateUserSessionRunning(appState, chatId)
-					break mainLoop
+				if currentSession.IsFlow() {
+					startOvertime(appState, chatId, currentSession, handlers)
+					continue mainLoop
 				}
-			} else {
-				currentSession.ActionsChannel = nil
-				log.Println("Session channel is closed. Aborting main loop...")
-				break mainLoop
+				record := phaseRecord(chatId, currentSession, domain.OutcomeCompleted)
+				currentSession.DecreaseSprintDuration()
+				if transitionFailed(currentSession.RestStarted()) {
+					continue mainLoop
+				}
+				addPhaseRecord(appState, record)
+				progress, progressErr := data.AddCompletedPomodoro(appState, chatId)
+				handlers.RestBegin(chatId, currentSession)
+				notifyPomodoroCompleted(handlers, chatId, currentSession, progress, progressErr)
 			}
-		default:
-			time.Sleep(1 * time.Second)
+			if action.RestFinished {
+				if !currentSession.IsRest() || !currentSession.HasRestEndTimePassed() {
+					// Stale action: the rest is not over.
+					continue mainLoop
+				}
+				record := phaseRecord(chatId, currentSession, domain.OutcomeCompleted)
+				if !finishRest(appState, chatId, currentSession, handlers) {
+					continue mainLoop
+				}
+				addPhaseRecord(appState, record)
+			}
+			// We update session running because it changed state
+			// (rest started or finished)
+			data.UpdateUserSessionRunning(appState, chatId)
+			scheduleNextAction(appState, chatId, currentSession)
+			continue mainLoop
+		}
 
-			isRest := currentSession.IsRest()
+		// The event was internal (current sprint or rest about to end)
+		if action.Warning {
+			left := currentSession.PhaseTimeLeft()
+			if currentSession.IsStopped() || currentSession.IsPhaseWarned() ||
+				left <= 0 || left > action.WarningLead {
+				// Stale action: the warning belongs to another phase.
+				continue mainLoop
+			}
+			currentSession.SetPhaseWarned()
+			handlers.PhaseEnding(chatId, currentSession, left)
+			// We update session running so that the warning is not repeated
+			// after a restart.
+			data.UpdateUserSessionRunning(appState, chatId)
+			continue mainLoop
+		}
 
-			if !isRest && currentSession.HasSprintEndTimePassed() {
-				currentSession.DecreaseSprintDuration()
+		// The event was external (current sprint or rest extended)
+		if action.Extended {
+			err := currentSession.Extend(action.Extension, action.MaxExtension)
+			handlers.PhaseExtended(chatId, currentSession, err)
+			if err == nil {
+				// We update session running because its timestamps changed
+				data.UpdateUserSessionRunning(appState, chatId)
+				scheduleNextAction(appState, chatId, currentSession)
+			}
+			continue mainLoop
+		}
 
-				// if currentSession.GetSprintDuration() < 0 {
-				if currentSession.SprintDurationFinished() {
-					currentSession.WritingActionChannel() <- domain.DispatchAction{Finished: true}
+		// The event was external (current sprint voided and restarted)
+		if action.Restarted {
+			if state := currentSession.State(); state != domain.StateSprint && state != domain.StateOvertime {
+				// Stale action: the sprint is over.
+				continue mainLoop
+			}
+			record := phaseRecord(chatId, currentSession, domain.OutcomeVoided)
+			if transitionFailed(currentSession.RestartSprint()) {
+				continue mainLoop
+			}
+			addPhaseRecord(appState, record)
+			handlers.SprintRestarted(chatId, currentSession)
+			// We update session running because its timestamps changed
+			data.UpdateUserSessionRunning(appState, chatId)
+			scheduleNextAction(appState, chatId, currentSession)
+			continue mainLoop
+		}
+
+		// The event was external (interruption of the sprint logged)
+		if action.Interrupted {
+			interruption, err := currentSession.Interrupt(chatId, action.InterruptionKind, action.InterruptionNote)
+			if err == nil {
+				data.AddInterruption(appState, interruption)
+				// We update session running because its counters changed
+				data.UpdateUserSessionRunning(appState, chatId)
+			}
+			handlers.InterruptionLogged(chatId, currentSession, interruption, err)
+			continue mainLoop
+		}
+
+		// The event was external (break taken, or overtime skipped)
+		if action.Break || (action.Skipped && currentSession.State() == domain.StateOvertime) {
+			if currentSession.State() != domain.StateOvertime {
+				// Stale action: the session is not in overtime anymore.
+				continue mainLoop
+			}
+			record := phaseRecord(chatId, currentSession, domain.OutcomeCompleted)
+			lastSprint := isLastSprint(appState, chatId, currentSession)
+			currentSession.DecreaseSprintDuration()
+			if lastSprint {
+				if transitionFailed(currentSession.SetFinished()) {
 					continue mainLoop
 				}
+				addPhaseRecord(appState, record)
+				progress, progressErr := data.AddCompletedPomodoro(appState, chatId)
+				handlers.SessionEnd(chatId, currentSession, PomodoroFinished)
+				notifyPomodoroCompleted(handlers, chatId, currentSession, progress, progressErr)
+				data.UpdateUserSessionRunning(appState, chatId)
+				break mainLoop
+			}
+			if transitionFailed(currentSession.TakeBreak()) {
+				continue mainLoop
+			}
+			addPhaseRecord(appState, record)
+			progress, progressErr := data.AddCompletedPomodoro(appState, chatId)
+			handlers.RestBegin(chatId, currentSession)
+			notifyPomodoroCompleted(handlers, chatId, currentSession, progress, progressErr)
+			// We update session running because it changed state (rest
+			// started)
+			data.UpdateUserSessionRunning(appState, chatId)
+			scheduleNextAction(appState, chatId, currentSession)
+			continue mainLoop
+		}
 
-				// if SprintDuration still >= 0 or is UnspecifiedSprintCardinality, we have rest now
-				currentSession.WritingActionChannel() <- domain.DispatchAction{RestStarted: true}
+		// The event was external (next sprint confirmed, or waiting skipped)
+		if action.Advance || (action.Skipped && currentSession.State() == domain.StateReady) {
+			if currentSession.State() != domain.StateReady {
+				// Stale action: the session is not waiting anymore.
+				continue mainLoop
+			}
+			if transitionFailed(currentSession.RestFinished()) {
 				continue mainLoop
-			} else if isRest && currentSession.HasRestEndTimePassed() {
+			}
+			handlers.RestFinished(chatId, currentSession)
+			// We update session running because it changed state (sprint
+			// started)
+			data.UpdateUserSessionRunning(appState, chatId)
+			scheduleNextAction(appState, chatId, currentSession)
+			continue mainLoop
+		}
 
-				currentSession.WritingActionChannel() <- domain.DispatchAction{RestFinished: true}
+		// The event was internal (nobody confirmed the next sprint in time)
+		if action.ReadyTimeout {
+			readySince, ok := currentSession.ReadySince()
+			_, timeout := data.GetManualAdvance(appState, chatId)
+			if !ok || timeout <= 0 || currentSession.Clock().Now().Sub(readySince) < timeout {
+				// Stale action: the session is not waiting (long enough).
 				continue mainLoop
 			}
+			if transitionFailed(currentSession.Pause()) {
+				continue mainLoop
+			}
+			handlers.SessionPaused(chatId, currentSession)
+			// We update session running because it changed state (paused)
+			data.UpdateUserSessionRunning(appState, chatId)
+			break mainLoop
+		}
+
+		// The event was external (current sprint or rest skipped)
+		if action.Skipped {
+			record := phaseRecord(chatId, currentSession, domain.OutcomeSkipped)
+			if currentSession.IsRest() {
+				if !finishRest(appState, chatId, currentSession, handlers) {
+					continue mainLoop
+				}
+			} else {
+				lastSprint := isLastSprint(appState, chatId, currentSession)
+				currentSession.DecreaseSprintDuration()
+				if lastSprint {
+					if transitionFailed(currentSession.SetFinished()) {
+						continue mainLoop
+					}
+					addPhaseRecord(appState, record)
+					handlers.SessionEnd(chatId, currentSession, PomodoroFinished)
+					data.UpdateUserSessionRunning(appState, chatId)
+					break mainLoop
+				}
+				if transitionFailed(currentSession.RestStarted()) {
+					continue mainLoop
+				}
+				handlers.RestBegin(chatId, currentSession)
+			}
+			addPhaseRecord(appState, record)
+			// We update session running because it changed state
+			// (rest started or finished)
+			data.UpdateUserSessionRunning(appState, chatId)
+			scheduleNextAction(appState, chatId, currentSession)
+			continue mainLoop
+		}
+
+		// The event was either external (paused/canceled) or internal (finished)
+		if action.Paused || action.Canceled || action.Finished {
+			if action.Paused {
+				if transitionFailed(currentSession.Pause()) {
+					continue mainLoop
+				}
+				handlers.SessionPaused(chatId, currentSession)
+			} else if action.Canceled {
+				record := phaseRecord(chatId, currentSession, domain.OutcomeCanceled)
+				if transitionFailed(currentSession.Cancel()) {
+					continue mainLoop
+				}
+				addPhaseRecord(appState, record)
+				handlers.SessionEnd(chatId, currentSession, PomodoroCanceled)
+			} else if action.Finished {
+				if currentSession.State() != domain.StateSprint || !currentSession.HasSprintEndTimePassed() {
+					// Stale action: the sprint is not over.
+					continue mainLoop
+				}
+				if currentSession.IsFlow() {
+					startOvertime(appState, chatId, currentSession, handlers)
+					continue mainLoop
+				}
+				record := phaseRecord(chatId, currentSession, domain.OutcomeCompleted)
+				currentSession.DecreaseSprintDuration()
+				if transitionFailed(currentSession.SetFinished()) {
+					continue mainLoop
+				}
+				addPhaseRecord(appState, record)
+				progress, progressErr := data.AddCompletedPomodoro(appState, chatId)
+				handlers.SessionEnd(chatId, currentSession, PomodoroFinished)
+				notifyPomodoroCompleted(handlers, chatId, currentSession, progress, progressErr)
+			}
+			// We update session running because it changed state
+			// (paused, canceled or finished)
+			data.UpdateUserSessionRunning(appState, chatId)
+			break mainLoop
 		}
 	}
+	// No timed action must reach the channel once it is closed.
+	scheduler.Unschedule(currentSession)
+	scheduler.Unschedule(warningKey{session: currentSession})
 	defer currentSession.ClearChannel()
 }
 
+// transitionFailed Log the error of a transition of the session, if any.
+// Such errors mean that the timer goroutine received an action that does not
+// apply to the state of the session.
+func transitionFailed(err error) bool {
+	if err != nil {
+		log.Printf("[SpawnSessionTimer] %v\n", err.Error())
+		return true
+	}
+	return false
+}
+
+// isLastSprint returns true if the session finishes once its current sprint
+// ends: it is the last one, or the session has no set number of sprints and
+// another one would go beyond the limits of the chat.
+func isLastSprint(appState *domain.AppState, chatId domain.ChatID, currentSession *domain.Session) bool {
+	return currentSession.IsLastSprint() ||
+		data.GetSessionLimits(appState, chatId).UnspecifiedSessionOver(currentSession)
+}
+
+// finishRest Move a session whose rest is over to its next sprint, or to
+// StateReady if the chat wants to confirm the start of the sprint. It returns
+// false if the session could not move.
+func finishRest(
+	appState *domain.AppState,
+	chatId domain.ChatID,
+	currentSession *domain.Session,
+	handlers SessionHandlers,
+) bool {
+	currentSession.AdvancePlanStep()
+
+	if manual, _ := data.GetManualAdvance(appState, chatId); manual {
+		if transitionFailed(currentSession.ReadyStarted()) {
+			return false
+		}
+		handlers.SessionReady(chatId, currentSession)
+		return true
+	}
+
+	if transitionFailed(currentSession.RestFinished()) {
+		return false
+	}
+	handlers.RestFinished(chatId, currentSession)
+	return true
+}
+
+// startOvertime Move a session in flow mode, whose sprint time is up, to
+// overtime. No timed action is scheduled until the user takes a break.
+func startOvertime(
+	appState *domain.AppState,
+	chatId domain.ChatID,
+	currentSession *domain.Session,
+	handlers SessionHandlers,
+) {
+	if transitionFailed(currentSession.OvertimeStarted()) {
+		return
+	}
+	handlers.OvertimeBegin(chatId, currentSession)
+	// We update session running because it changed state (overtime started)
+	data.UpdateUserSessionRunning(appState, chatId)
+}
+
+// notifyPomodoroCompleted Call the PomodoroCompleted handler, unless the
+// progress could not be counted.
+func notifyPomodoroCompleted(
+	handlers SessionHandlers,
+	chatId domain.ChatID,
+	currentSession *domain.Session,
+	progress domain.GoalProgress,
+	err error,
+) {
+	if err != nil {
+		log.Printf("[SpawnSessionTimer] cannot count the pomodoro: %v\n", err.Error())
+		return
+	}
+	handlers.PomodoroCompleted(chatId, currentSession, progress)
+}
+
+// warningKey identifies the pre-end warning of a session in domain.Scheduler
+// (the action that ends the phase is keyed by the session itself).
+type warningKey struct {
+	session *domain.Session
+}
+
+// scheduleNextAction Schedule the action that ends the current sprint (or
+// rest) of the session: RestStarted, or Finished if it is the last sprint,
+// during a sprint; RestFinished during a rest; ReadyTimeout while waiting
+// for the next sprint, if the chat wants the session paused after a while.
+// The pre-end warning of the phase is scheduled as well, see
+// scheduleWarning.
+//
+// The actions are delivered to the session's ActionsChannel, so it is still
+// the timer goroutine of the session to act on them.
+func scheduleNextAction(appState *domain.AppState, chatId domain.ChatID, currentSession *domain.Session) {
+	scheduler := appState.Scheduler
+	switch currentSession.State() {
+	case domain.StateOvertime:
+		// The sprint goes on until the user takes a break.
+		scheduler.Unschedule(currentSession)
+		scheduler.Unschedule(warningKey{session: currentSession})
+		return
+	case domain.StateReady:
+		scheduler.Unschedule(warningKey{session: currentSession})
+		readySince, _ := currentSession.ReadySince()
+		if _, timeout := data.GetManualAdvance(appState, chatId); timeout > 0 {
+			scheduleAction(scheduler, currentSession, readySince.Add(timeout), currentSession,
+				domain.DispatchAction{ReadyTimeout: true})
+		} else {
+			scheduler.Unschedule(currentSession)
+		}
+		return
+	}
+	scheduleWarning(scheduler, currentSession, data.GetWarnLead(appState, chatId))
+
+	var at *time.Time
+	var action domain.DispatchAction
+
+	if currentSession.IsRest() {
+		at = currentSession.EndNextRestTimestamp()
+		action = domain.DispatchAction{RestFinished: true}
+	} else {
+		at = currentSession.EndNextSprintTimestamp()
+		if isLastSprint(appState, chatId, currentSession) {
+			action = domain.DispatchAction{Finished: true}
+		} else {
+			action = domain.DispatchAction{RestStarted: true}
+		}
+	}
+
+	if at == nil {
+		log.Println("[PROBLEM] no timestamp to schedule the next action.")
+		return
+	}
+
+	scheduleAction(scheduler, currentSession, *at, currentSession, action)
+}
+
+// scheduleWarning Schedule the Warning action of the current sprint (or rest)
+// of the session, `lead` before its end.
+//
+// Nothing is scheduled if the chat does not want to be warned, if the phase
+// has already been warned (e.g., before a pause or a restart) or if it is not
+// longer than the lead itself.
+func scheduleWarning(scheduler domain.Scheduler, currentSession *domain.Session, lead time.Duration) {
+	key := warningKey{session: currentSession}
+	end := currentSession.PhaseEndTimestamp()
+
+	if lead <= 0 || end == nil || currentSession.IsPhaseWarned() ||
+		currentSession.PhaseDurationSet() <= lead {
+		scheduler.Unschedule(key)
+		return
+	}
+
+	action := domain.DispatchAction{Warning: true, WarningLead: lead}
+	scheduleAction(scheduler, key, end.Add(-lead), currentSession, action)
+}
+
+// scheduleAction Schedule the delivery of the action to the session's
+// ActionsChannel at the given time, under the given key.
+//
+// The action is handed off (see Session.ActionHandOff), so that a full channel
+// delays it without blocking the scheduler.
+func scheduleAction(
+	scheduler domain.Scheduler,
+	key any,
+	at time.Time,
+	currentSession *domain.Session,
+	action domain.DispatchAction,
+) {
+	handOff := currentSession.ActionHandOff()
+	scheduler.Schedule(key, at, func() {
+		handOff(action)
+	})
+}
+
+// phaseRecord returns the current sprint (or rest) of the session as a record
+// of the history of the chat, ended now with the given outcome (nil if there
+// is nothing to record). It is taken before the session moves on, and added
+// with addPhaseRecord only once the session did.
+func phaseRecord(
+	chatId domain.ChatID,
+	currentSession *domain.Session,
+	outcome domain.PhaseOutcome,
+) *domain.HistoryRecord {
+	if currentSession.PhaseStartTimestamp() == nil || currentSession.State() == domain.StateReady {
+		// The session never started, or it is between a rest and the next
+		// sprint: there is nothing to record.
+		return nil
+	}
+	record := currentSession.CurrentPhaseRecord(chatId, outcome)
+	return &record
+}
+
+// addPhaseRecord Append the record (if any) to the history of the chat.
+func addPhaseRecord(appState *domain.AppState, record *domain.HistoryRecord) {
+	if record != nil {
+		data.AddHistoryRecord(appState, *record)
+	}
+}
+
+// CancelPausedSession Cancel a session that has no timer running (i.e., a
+// paused session), recording the interrupted phase in the history.
+func CancelPausedSession(
+	appState *domain.AppState,
+	chatId domain.ChatID,
+	currentSession *domain.Session,
+) error {
+	if !currentSession.State().CanTransitionTo(domain.StateCanceled) {
+		return domain.InvalidTransition{From: currentSession.State(), To: domain.StateCanceled}
+	}
+	record := phaseRecord(chatId, currentSession, domain.OutcomeCanceled)
+	if err := currentSession.Cancel(); err != nil {
+		return err
+	}
+	addPhaseRecord(appState, record)
+	return nil
+}
+
+// InterruptPausedSession Log an interruption of the sprint of a session that
+// has no timer running (i.e., a paused session), storing it right away.
+func InterruptPausedSession(
+	appState *domain.AppState,
+	chatId domain.ChatID,
+	currentSession *domain.Session,
+	kind domain.InterruptionKind,
+	note string,
+) (domain.Interruption, error) {
+	interruption, err := currentSession.Interrupt(chatId, kind, note)
+	if err != nil {
+		return domain.Interruption{}, err
+	}
+	data.AddInterruption(appState, interruption)
+	return interruption, nil
+}
+
 func PauseSession(currentSession *domain.Session) error {
-	if currentSession.IsPaused() {
-		return errors.New("sessionDefault already paused")
+	if !currentSession.State().IsRunning() {
+		return domain.InvalidTransition{From: currentSession.State(), To: domain.StatePausedSprint}
 	}
 
 	currentSession.WritingActionChannel() <- domain.DispatchAction{Paused: true}
 	return nil
 }
 
+// SkipPhase End the current sprint (or rest) of a running session now, moving
+// to the next one.
+func SkipPhase(currentSession *domain.Session) error {
+	if currentSession.IsStopped() {
+		return errors.New("session not running")
+	}
+
+	currentSession.WritingActionChannel() <- domain.DispatchAction{Skipped: true}
+	return nil
+}
+
+// SkippedState returns the state the session moves to once its current phase
+// is skipped (see SkipPhase).
+func SkippedState(appState *domain.AppState, chatId domain.ChatID, currentSession *domain.Session) domain.SessionState {
+	switch {
+	case currentSession.State() == domain.StateReady:
+		return domain.StateSprint
+	case currentSession.IsRest():
+		if manual, _ := data.GetManualAdvance(appState, chatId); manual {
+			return domain.StateReady
+		}
+		return domain.StateSprint
+	case isLastSprint(appState, chatId, currentSession):
+		return domain.StateFinished
+	default:
+		return domain.StateRest
+	}
+}
+
+// TakeBreak End the overtime of a session in flow mode, beginning its rest
+// (or finishing it, after the last sprint).
+func TakeBreak(currentSession *domain.Session) error {
+	if currentSession.State() != domain.StateOvertime {
+		return domain.InvalidTransition{From: currentSession.State(), To: domain.StateRest}
+	}
+
+	currentSession.WritingActionChannel() <- domain.DispatchAction{Break: true}
+	return nil
+}
+
+// AdvanceSession Start the next sprint of a session that is waiting for it
+// (see SessionHandlers.SessionReady).
+func AdvanceSession(currentSession *domain.Session) error {
+	if currentSession.State() != domain.StateReady {
+		return domain.InvalidTransition{From: currentSession.State(), To: domain.StateSprint}
+	}
+
+	currentSession.WritingActionChannel() <- domain.DispatchAction{Advance: true}
+	return nil
+}
+
+// RestartSprint Void the current sprint of a running session and start it
+// over, keeping the number of sprints left. The voided sprint is recorded in
+// the history with OutcomeVoided.
+func RestartSprint(currentSession *domain.Session) error {
+	if state := currentSession.State(); state != domain.StateSprint && state != domain.StateOvertime {
+		return domain.NotInSprint{State: state}
+	}
+
+	currentSession.WritingActionChannel() <- domain.DispatchAction{Restarted: true}
+	return nil
+}
+
+// InterruptSession Log an interruption of the current sprint of a running
+// session. The outcome is notified through the InterruptionLogged handler.
+// Paused sessions are handled by InterruptPausedSession.
+func InterruptSession(currentSession *domain.Session, kind domain.InterruptionKind, note string) error {
+	if state := currentSession.State(); state != domain.StateSprint && state != domain.StateOvertime {
+		return domain.NotInSprint{State: state}
+	}
+
+	currentSession.WritingActionChannel() <- domain.DispatchAction{
+		Interrupted:      true,
+		InterruptionKind: kind,
+		InterruptionNote: note,
+	}
+	return nil
+}
+
+// ExtendPhase Push the end of the current sprint (or rest) of a running session
+// forward. The outcome is notified through the PhaseExtended handler.
+func ExtendPhase(currentSession *domain.Session, extension time.Duration, maxExtension time.Duration) error {
+	if currentSession.IsStopped() {
+		return errors.New("session not running")
+	}
+
+	currentSession.WritingActionChannel() <- domain.DispatchAction{
+		Extended:     true,
+		Extension:    extension,
+		MaxExtension: maxExtension,
+	}
+	return nil
+}
+
 func CancelSession(currentSession *domain.Session) error {
-	if currentSession.IsCanceled() {
-		return errors.New("sessionDefault already canceled")
+	if !currentSession.State().CanTransitionTo(domain.StateCanceled) {
+		return domain.InvalidTransition{From: currentSession.State(), To: domain.StateCanceled}
 	}
 
 	currentSession.WritingActionChannel() <- domain.DispatchAction{Canceled: true}
@@ -160,31 +700,20 @@ func ResumeSession(
 	appState *domain.AppState,
 	userId domain.ChatID,
 	currentSession *domain.Session,
-	restBeginHandler func(id domain.ChatID, session *domain.Session),
-	restFinishedHandler func(id domain.ChatID, session *domain.Session),
-	endSessionHandler func(id domain.ChatID, session *domain.Session, endKind PomodoroEndKind),
-	pauseSessionHandler func(id domain.ChatID, session *domain.Session),
+	handlers SessionHandlers,
 ) error {
 	if currentSession.IsZero() {
 		return errors.New("the session is effectively nil")
 	}
-	if !currentSession.IsStopped() {
-		return errors.New("session already running")
-	}
-	if currentSession.IsCanceled() {
-		return errors.New("session was canceled")
+	if err := currentSession.Resume(); err != nil {
+		return err
 	}
 
-	currentSession.Resume()
-
 	go SpawnSessionTimer(
 		appState,
 		userId,
 		currentSession,
-		restBeginHandler,
-		restFinishedHandler,
-		endSessionHandler,
-		pauseSessionHandler,
+		handlers,
 	)
 	return nil
 }
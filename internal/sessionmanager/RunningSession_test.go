@@ -0,0 +1,671 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package sessionmanager
+
+import (
+	"GoforPomodoro/internal/data"
+	"GoforPomodoro/internal/domain"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedEvents struct {
+	lock   sync.Mutex
+	events []string
+}
+
+func (r *recordedEvents) add(event string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recordedEvents) get() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]string{}, r.events...)
+}
+
+// testStart is when the sessions of the tests start.
+var testStart = time.Date(2022, time.October, 17, 9, 0, 0, 0, time.UTC)
+
+func newTestAppState(clock domain.Clock) *domain.AppState {
+	return &domain.AppState{
+		Clock:         clock,
+		Scheduler:     NewScheduler(clock),
+		UsersSettings: make(map[domain.ChatID]*domain.Settings),
+	}
+}
+
+// channelHandlers returns handlers that send the name of each event to the
+// channel.
+func channelHandlers(events chan<- string) SessionHandlers {
+	return SessionHandlers{
+		RestBegin:     func(id domain.ChatID, session *domain.Session) { events <- "rest" },
+		RestFinished:  func(id domain.ChatID, session *domain.Session) { events <- "sprint" },
+		SessionEnd:    func(id domain.ChatID, session *domain.Session, endKind PomodoroEndKind) { events <- "end" },
+		SessionPaused: func(id domain.ChatID, session *domain.Session) { events <- "paused" },
+		PhaseExtended: func(id domain.ChatID, session *domain.Session, err error) {
+			if err == nil {
+				events <- "extended"
+			} else {
+				events <- "not extended"
+			}
+		},
+		OvertimeBegin:   func(id domain.ChatID, session *domain.Session) { events <- "overtime" },
+		SessionReady:    func(id domain.ChatID, session *domain.Session) { events <- "ready" },
+		SprintRestarted: func(id domain.ChatID, session *domain.Session) { events <- "restarted" },
+		InterruptionLogged: func(id domain.ChatID, session *domain.Session, interruption domain.Interruption, err error) {
+			events <- fmt.Sprintf("interruption %v", err)
+		},
+		PhaseEnding: func(id domain.ChatID, session *domain.Session, left time.Duration) {
+			events <- fmt.Sprintf("warning %v", left)
+		},
+		PomodoroCompleted: func(id domain.ChatID, session *domain.Session, progress domain.GoalProgress) {},
+	}
+}
+
+// startTestSession starts the session in chat 1, returning the channel its
+// events are sent to (see channelHandlers).
+func startTestSession(t *testing.T, appState *domain.AppState, sdd domain.SessionDefaultData) (*domain.Session, <-chan string) {
+	t.Helper()
+
+	session := sdd.ToInitData().ToSession().SetClock(appState.GetClock()).InitChannel()
+
+	events := make(chan string, 10)
+	if err := StartSession(appState, 1, session, channelHandlers(events)); err != nil {
+		t.Fatalf("StartSession returned error: %v", err)
+	}
+	return session, events
+}
+
+// expectEvent fails the test unless the next event is the expected one.
+func expectEvent(t *testing.T, events <-chan string, expected string) {
+	t.Helper()
+
+	select {
+	case got := <-events:
+		if got != expected {
+			t.Fatalf("expected event %s, got %s", expected, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("event %s never happened", expected)
+	}
+}
+
+func TestSessionRunsToCompletion(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	session := domain.SessionDefaultData{
+		SprintDurationSet:   3,
+		PomodoroDurationSet: 25 * 60,
+		RestDurationSet:     5 * 60,
+	}.ToInitData().ToSession().SetClock(clock).InitChannel()
+
+	events := new(recordedEvents)
+	finished := make(chan time.Time, 1)
+
+	err := StartSession(
+		appState,
+		1,
+		session,
+		SessionHandlers{
+			RestBegin:    func(id domain.ChatID, session *domain.Session) { events.add("rest") },
+			RestFinished: func(id domain.ChatID, session *domain.Session) { events.add("sprint") },
+			SessionEnd: func(id domain.ChatID, session *domain.Session, endKind PomodoroEndKind) {
+				if endKind == PomodoroFinished {
+					events.add("finished")
+				} else {
+					events.add("canceled")
+				}
+				finished <- clock.Now()
+			},
+			SessionPaused: func(id domain.ChatID, session *domain.Session) { events.add("paused") },
+			PhaseExtended: func(id domain.ChatID, session *domain.Session, err error) { events.add("extended") },
+			PomodoroCompleted: func(id domain.ChatID, session *domain.Session, progress domain.GoalProgress) {
+				events.add(fmt.Sprintf("completed %d", progress.Today))
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("StartSession returned error: %v", err)
+	}
+
+	var end time.Time
+simulation:
+	for {
+		select {
+		case end = <-finished:
+			break simulation
+		case <-clock.Sleepers(1):
+			clock.Advance(time.Second)
+		}
+	}
+
+	expected := []string{
+		"rest", "completed 1", "sprint",
+		"rest", "completed 2", "sprint",
+		"finished", "completed 3",
+	}
+	got := events.get()
+	if len(got) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected events %v, got %v", expected, got)
+		}
+	}
+
+	elapsed := end.Sub(testStart)
+	if elapsed < 85*time.Minute || elapsed > 85*time.Minute+5*time.Second {
+		t.Fatalf("session should have lasted about 85 minutes, instead it lasted %v", elapsed)
+	}
+	if !session.IsFinished() {
+		t.Fatalf("session should be finished")
+	}
+}
+
+func TestPausedSessionKeepsTimeLeft(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	session, events := startTestSession(t, appState, domain.DefaultSession())
+
+	<-clock.Sleepers(1)
+	clock.Advance(10 * time.Minute)
+	if err := PauseSession(session); err != nil {
+		t.Fatalf("PauseSession returned error: %v", err)
+	}
+	expectEvent(t, events, "paused")
+
+	clock.Advance(time.Hour)
+	if left := session.GetPomodoroDuration(); left != 15*60 {
+		t.Fatalf("time should not pass while paused, instead %d seconds are left", left)
+	}
+	if session.HasSprintEndTimePassed() {
+		t.Fatalf("the sprint of a paused session should not end")
+	}
+}
+
+func TestSkipPhase(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	session, events := startTestSession(t, appState, domain.SessionDefaultData{
+		SprintDurationSet:   2,
+		PomodoroDurationSet: 25 * 60,
+		RestDurationSet:     5 * 60,
+	})
+
+	clock.Advance(10 * time.Minute)
+	if err := SkipPhase(session); err != nil {
+		t.Fatalf("SkipPhase returned error: %v", err)
+	}
+	expectEvent(t, events, "rest")
+
+	if err := SkipPhase(session); err != nil {
+		t.Fatalf("SkipPhase returned error: %v", err)
+	}
+	expectEvent(t, events, "sprint")
+
+	if err := SkipPhase(session); err != nil {
+		t.Fatalf("SkipPhase returned error: %v", err)
+	}
+	expectEvent(t, events, "end")
+
+	if !session.IsFinished() {
+		t.Fatalf("session should be finished after skipping its last sprint")
+	}
+}
+
+func TestExtendPhase(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	session, events := startTestSession(t, appState, domain.DefaultSession())
+
+	clock.Advance(24 * time.Minute)
+	if err := ExtendPhase(session, 5*time.Minute, 10*time.Minute); err != nil {
+		t.Fatalf("ExtendPhase returned error: %v", err)
+	}
+	expectEvent(t, events, "extended")
+
+	if err := ExtendPhase(session, 10*time.Minute, 10*time.Minute); err != nil {
+		t.Fatalf("ExtendPhase returned error: %v", err)
+	}
+	expectEvent(t, events, "not extended")
+	if err := ExtendPhase(session, -time.Minute, 10*time.Minute); err != nil {
+		t.Fatalf("ExtendPhase returned error: %v", err)
+	}
+	expectEvent(t, events, "not extended")
+
+	// The sprint would have ended at minute 25 without the extension.
+	<-clock.Sleepers(1)
+	clock.Advance(2 * time.Minute)
+	if left := session.GetPomodoroDuration(); left != 4*60 {
+		t.Fatalf("4 minutes should be left, instead %d seconds are left", left)
+	}
+
+	clock.Advance(4 * time.Minute)
+	expectEvent(t, events, "rest")
+}
+
+func TestSessionPlanRunsEachStep(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	plan := domain.SessionPlan{
+		{PomodoroDuration: 50 * 60, RestDuration: 10 * 60},
+		{PomodoroDuration: 25 * 60, RestDuration: 5 * 60},
+		{PomodoroDuration: 15 * 60},
+	}
+	session := plan.ToDefaultData().ToInitData().ToSession().SetClock(clock).InitChannel()
+
+	events := new(recordedEvents)
+	finished := make(chan time.Time, 1)
+	handlers := SessionHandlers{
+		RestBegin: func(id domain.ChatID, session *domain.Session) {
+			events.add(fmt.Sprintf("rest %dm", session.GetCurrentRestDurationSet()/60))
+		},
+		RestFinished: func(id domain.ChatID, session *domain.Session) {
+			events.add(fmt.Sprintf("sprint %dm", session.GetPomodoroDurationSet()/60))
+		},
+		SessionEnd: func(id domain.ChatID, session *domain.Session, endKind PomodoroEndKind) {
+			finished <- clock.Now()
+		},
+		SessionPaused:     func(id domain.ChatID, session *domain.Session) {},
+		PhaseExtended:     func(id domain.ChatID, session *domain.Session, err error) {},
+		PomodoroCompleted: func(id domain.ChatID, session *domain.Session, progress domain.GoalProgress) {},
+	}
+
+	if err := StartSession(appState, 1, session, handlers); err != nil {
+		t.Fatalf("StartSession returned error: %v", err)
+	}
+
+	var end time.Time
+simulation:
+	for {
+		select {
+		case end = <-finished:
+			break simulation
+		case <-clock.Sleepers(1):
+			clock.Advance(time.Second)
+		}
+	}
+
+	expected := []string{"rest 10m", "sprint 25m", "rest 5m", "sprint 15m"}
+	got := events.get()
+	if len(got) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected events %v, got %v", expected, got)
+		}
+	}
+
+	elapsed := end.Sub(testStart)
+	expectedElapsed := time.Duration(plan.DurationInSeconds()) * time.Second
+	if elapsed < expectedElapsed || elapsed > expectedElapsed+5*time.Second {
+		t.Fatalf("session should have lasted about %v, instead it lasted %v", expectedElapsed, elapsed)
+	}
+	if session.GetPlanStep() != 2 {
+		t.Fatalf("session should be at the last step of its plan, instead it is at step %d", session.GetPlanStep())
+	}
+}
+
+func TestPhaseEndingWarnedOncePerPhase(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+	data.SetWarnLead(appState, 1, 2)
+
+	_, events := startTestSession(t, appState, domain.SessionDefaultData{
+		SprintDurationSet:   2,
+		PomodoroDurationSet: 25 * 60,
+		RestDurationSet:     5 * 60,
+	})
+
+	steps := []struct {
+		advance time.Duration
+		event   string
+	}{
+		{23 * time.Minute, "warning 2m0s"},
+		{2 * time.Minute, "rest"},
+		{3 * time.Minute, "warning 2m0s"},
+		{2 * time.Minute, "sprint"},
+		{23 * time.Minute, "warning 2m0s"},
+		{2 * time.Minute, "end"},
+	}
+	for _, step := range steps {
+		<-clock.Sleepers(1)
+		clock.Advance(step.advance)
+		expectEvent(t, events, step.event)
+	}
+}
+
+func TestPhaseEndingNotRepeatedAfterRestart(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+	data.SetWarnLead(appState, 1, 2)
+
+	for _, warned := range []bool{false, true} {
+		// A sprint restored one minute before its end.
+		session := domain.SessionInitData{
+			SprintDurationSet:      2,
+			PomodoroDurationSet:    25 * 60,
+			RestDurationSet:        5 * 60,
+			SprintDuration:         1,
+			EndNextSprintTimestamp: clock.Now().Add(time.Minute),
+			EndNextRestTimestamp:   clock.Now().Add(6 * time.Minute),
+			PhaseWarned:            warned,
+			State:                  domain.StateSprint,
+		}.ToSession().SetClock(clock).InitChannel()
+
+		events := make(chan string, 10)
+		go SpawnSessionTimer(appState, 1, session, channelHandlers(events))
+
+		if !warned {
+			// The warning is late, but there is still time to send it.
+			expectEvent(t, events, "warning 1m0s")
+		}
+		<-clock.Sleepers(1)
+		clock.Advance(time.Minute)
+		expectEvent(t, events, "rest")
+
+		if err := CancelSession(session); err != nil {
+			t.Fatalf("CancelSession returned error: %v", err)
+		}
+		expectEvent(t, events, "end")
+	}
+}
+
+func TestSkippedState(t *testing.T) {
+	start := func(session *domain.Session) error { return session.Start() }
+	rest := func(session *domain.Session) error {
+		if err := session.Start(); err != nil {
+			return err
+		}
+		return session.RestStarted()
+	}
+	lastSprint := func(session *domain.Session) error {
+		session.DecreaseSprintDuration()
+		return session.Start()
+	}
+	ready := func(session *domain.Session) error {
+		if err := rest(session); err != nil {
+			return err
+		}
+		return session.ReadyStarted()
+	}
+
+	tests := []struct {
+		manual   bool
+		prepare  func(session *domain.Session) error
+		expected domain.SessionState
+	}{
+		{false, start, domain.StateRest},
+		{false, lastSprint, domain.StateFinished},
+		{false, rest, domain.StateSprint},
+		{true, rest, domain.StateReady},
+		{true, ready, domain.StateSprint},
+	}
+	for _, test := range tests {
+		appState := newTestAppState(domain.NewFakeClock(testStart))
+		data.SetManualAdvance(appState, 1, test.manual, 0)
+
+		session := domain.SessionDefaultData{
+			SprintDurationSet:   2,
+			PomodoroDurationSet: 25 * 60,
+			RestDurationSet:     5 * 60,
+		}.ToInitData().ToSession()
+		if err := test.prepare(session); err != nil {
+			t.Fatal(err)
+		}
+		if got := SkippedState(appState, 1, session); got != test.expected {
+			t.Errorf("expected a skip to lead to %v from %v, got %v", test.expected, session.State(), got)
+		}
+	}
+}
+func TestFlowSessionWaitsForBreak(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	sdd := domain.DefaultSession()
+	sdd.SprintDurationSet = 2
+	sdd.Flow = true
+	session, events := startTestSession(t, appState, sdd)
+
+	<-clock.Sleepers(1)
+	clock.Advance(25 * time.Minute)
+	expectEvent(t, events, "overtime")
+
+	if err := TakeBreak(session); err != nil {
+		t.Fatalf("TakeBreak returned error: %v", err)
+	}
+	expectEvent(t, events, "rest")
+	if rest := session.GetCurrentRestDurationSet(); rest != 5*60 {
+		t.Fatalf("rest should last 5 minutes, instead it lasts %d seconds", rest)
+	}
+
+	<-clock.Sleepers(1)
+	clock.Advance(5 * time.Minute)
+	expectEvent(t, events, "sprint")
+
+	// The last sprint goes in overtime as well.
+	<-clock.Sleepers(1)
+	clock.Advance(25 * time.Minute)
+	expectEvent(t, events, "overtime")
+	clock.Advance(time.Hour)
+	select {
+	case got := <-events:
+		t.Fatalf("no event should happen in overtime, got %s", got)
+	default:
+	}
+
+	if err := TakeBreak(session); err != nil {
+		t.Fatalf("TakeBreak returned error: %v", err)
+	}
+	expectEvent(t, events, "end")
+	if !session.IsFinished() {
+		t.Fatalf("session should be finished after the break of its last sprint")
+	}
+}
+
+func TestManualAdvanceWaitsForConfirmation(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+	data.SetManualAdvance(appState, 1, true, 10)
+
+	sdd := domain.DefaultSession()
+	sdd.SprintDurationSet = 3
+	session, events := startTestSession(t, appState, sdd)
+
+	if err := AdvanceSession(session); err == nil {
+		t.Fatalf("AdvanceSession should fail during a sprint")
+	}
+
+	<-clock.Sleepers(1)
+	clock.Advance(25 * time.Minute)
+	expectEvent(t, events, "rest")
+	<-clock.Sleepers(1)
+	clock.Advance(5 * time.Minute)
+	expectEvent(t, events, "ready")
+
+	if err := AdvanceSession(session); err != nil {
+		t.Fatalf("AdvanceSession returned error: %v", err)
+	}
+	expectEvent(t, events, "sprint")
+	if left := session.PhaseTimeLeft(); left != 25*time.Minute {
+		t.Fatalf("the confirmed sprint should last 25 minutes, %v are left", left)
+	}
+
+	<-clock.Sleepers(1)
+	clock.Advance(25 * time.Minute)
+	expectEvent(t, events, "rest")
+	<-clock.Sleepers(1)
+	clock.Advance(5 * time.Minute)
+	expectEvent(t, events, "ready")
+
+	// Nobody confirms the last sprint: the session is paused.
+	<-clock.Sleepers(1)
+	clock.Advance(10 * time.Minute)
+	expectEvent(t, events, "paused")
+	if session.State() != domain.StatePausedSprint {
+		t.Fatalf("session should be paused before its sprint, it is %v", session.State())
+	}
+	if left := session.PhaseTimeLeft(); left != 25*time.Minute {
+		t.Fatalf("the paused sprint should still last 25 minutes, %v are left", left)
+	}
+}
+
+func TestInterruptSessionOnlyDuringSprints(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	session, events := startTestSession(t, appState, domain.DefaultSession())
+
+	if err := InterruptSession(session, domain.InterruptionInternal, "email"); err != nil {
+		t.Fatalf("InterruptSession returned error: %v", err)
+	}
+	expectEvent(t, events, "interruption <nil>")
+
+	<-clock.Sleepers(1)
+	clock.Advance(25 * time.Minute)
+	expectEvent(t, events, "rest")
+	if err := InterruptSession(session, domain.InterruptionExternal, ""); err == nil {
+		t.Fatalf("InterruptSession should fail during a rest")
+	}
+
+	if got := session.GetInterruptions(); got.Total() != 1 {
+		t.Fatalf("1 interruption should be logged, instead %v are", got)
+	}
+}
+
+func TestInterruptPausedSession(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	session, events := startTestSession(t, appState, domain.DefaultSession())
+
+	<-clock.Sleepers(1)
+	clock.Advance(10 * time.Minute)
+	if err := PauseSession(session); err != nil {
+		t.Fatalf("PauseSession returned error: %v", err)
+	}
+	expectEvent(t, events, "paused")
+
+	interruption, err := InterruptPausedSession(appState, 1, session, domain.InterruptionExternal, "door")
+	if err != nil {
+		t.Fatalf("InterruptPausedSession returned error: %v", err)
+	}
+	if !interruption.SprintStart.Equal(testStart) || interruption.Note != "door" {
+		t.Fatalf("unexpected interruption %+v", interruption)
+	}
+	if got := session.GetInterruptions(); got.External != 1 {
+		t.Fatalf("1 external interruption should be logged, instead %v are", got)
+	}
+	if session.State() != domain.StatePausedSprint {
+		t.Fatalf("session should still be paused, it is %v", session.State())
+	}
+}
+
+func TestRestartSprintKeepsSprintCount(t *testing.T) {
+	clock := domain.NewFakeClock(testStart)
+	appState := newTestAppState(clock)
+
+	sdd := domain.DefaultSession()
+	sdd.SprintDurationSet = 2
+	session, events := startTestSession(t, appState, sdd)
+
+	<-clock.Sleepers(1)
+	clock.Advance(20 * time.Minute)
+	sprintsLeft := session.GetSprintDuration()
+	if err := RestartSprint(session); err != nil {
+		t.Fatalf("RestartSprint returned error: %v", err)
+	}
+	expectEvent(t, events, "restarted")
+	if left := session.PhaseTimeLeft(); left != 25*time.Minute {
+		t.Fatalf("the restarted sprint should last 25 minutes, %v are left", left)
+	}
+	if session.GetSprintDuration() != sprintsLeft {
+		t.Fatalf("the sprints left changed from %d to %d", sprintsLeft, session.GetSprintDuration())
+	}
+
+	// The old end of the sprint does not end the restarted one.
+	<-clock.Sleepers(1)
+	clock.Advance(5 * time.Minute)
+	select {
+	case got := <-events:
+		t.Fatalf("no event should happen at the old end of the sprint, got %s", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+	<-clock.Sleepers(1)
+	clock.Advance(20 * time.Minute)
+	expectEvent(t, events, "rest")
+	if err := RestartSprint(session); err == nil {
+		t.Fatalf("RestartSprint should fail during a rest")
+	}
+}
+
+func TestUnspecifiedSessionFinishesAtLimit(t *testing.T) {
+	start := time.Date(2022, time.October, 17, 9, 0, 0, 0, time.UTC)
+	clock := domain.NewFakeClock(start)
+	appState := newTestAppState(clock)
+	appState.SessionLimits = domain.SessionLimits{MaxUnspecifiedMinutes: 60}
+
+	session := domain.SessionDefaultData{
+		SprintDurationSet:   domain.UnspecifiedSprintCardinality,
+		PomodoroDurationSet: 25 * 60,
+		RestDurationSet:     5 * 60,
+	}.ToInitData().ToSession().SetClock(clock).InitChannel()
+
+	events := make(chan string, 10)
+	if err := StartSession(appState, 1, session, channelHandlers(events)); err != nil {
+		t.Fatalf("StartSession returned error: %v", err)
+	}
+
+	expectEvent := func(expected string) {
+		select {
+		case got := <-events:
+			if got != expected {
+				t.Fatalf("expected event %s, got %s", expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %s never happened", expected)
+		}
+	}
+
+	<-clock.Sleepers(1)
+	clock.Advance(25 * time.Minute)
+	expectEvent("rest")
+	<-clock.Sleepers(1)
+	clock.Advance(5 * time.Minute)
+	expectEvent("sprint")
+
+	// A third sprint would end after 85 minutes, beyond the limit.
+	<-clock.Sleepers(1)
+	clock.Advance(25 * time.Minute)
+	expectEvent("end")
+	if !session.IsFinished() {
+		t.Fatalf("session should be finished at the limit")
+	}
+}
@@ -0,0 +1,176 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package sessionmanager
+
+import (
+	"GoforPomodoro/internal/domain"
+	"container/heap"
+	"time"
+)
+
+// Scheduler fires callbacks at their deadlines.
+//
+// All the deadlines are kept in a min-heap served by a single goroutine,
+// which waits on a single timer for the earliest one. Callbacks are run by
+// such goroutine, so they are expected to return quickly.
+type Scheduler struct {
+	clock domain.Clock
+
+	requestChan chan interface{}
+}
+
+var _ domain.Scheduler = &Scheduler{}
+
+func NewScheduler(clock domain.Clock) *Scheduler {
+	scheduler := &Scheduler{
+		clock:       clock,
+		requestChan: make(chan interface{}),
+	}
+	go scheduler.run()
+	return scheduler
+}
+
+type scheduleRequest struct {
+	key          any
+	at           time.Time
+	fire         func()
+	responseChan chan struct{}
+}
+
+type unscheduleRequest struct {
+	key          any
+	responseChan chan struct{}
+}
+
+func (s *Scheduler) Schedule(key any, at time.Time, fire func()) {
+	responseChan := make(chan struct{})
+	s.requestChan <- scheduleRequest{
+		key:          key,
+		at:           at,
+		fire:         fire,
+		responseChan: responseChan,
+	}
+	<-responseChan
+}
+
+func (s *Scheduler) Unschedule(key any) {
+	responseChan := make(chan struct{})
+	s.requestChan <- unscheduleRequest{
+		key:          key,
+		responseChan: responseChan,
+	}
+	<-responseChan
+}
+
+func (s *Scheduler) run() {
+	queue := deadlineQueue{}
+	entries := make(map[any]*deadline)
+
+	// The timer is armed for the head of the queue (at armedAt), if any.
+	var timer domain.Timer
+	var armed bool
+	var armedAt time.Time
+	for {
+		var timerChan <-chan time.Time
+		if armed {
+			timerChan = timer.C()
+		}
+
+		select {
+		case req := <-s.requestChan:
+			switch r := req.(type) {
+			case scheduleRequest:
+				if entry, ok := entries[r.key]; ok {
+					heap.Remove(&queue, entry.index)
+				}
+				entry := &deadline{key: r.key, at: r.at, fire: r.fire}
+				heap.Push(&queue, entry)
+				entries[r.key] = entry
+				r.responseChan <- struct{}{}
+			case unscheduleRequest:
+				if entry, ok := entries[r.key]; ok {
+					heap.Remove(&queue, entry.index)
+					delete(entries, r.key)
+				}
+				r.responseChan <- struct{}{}
+			}
+		case <-timerChan:
+			armed = false
+			now := s.clock.Now()
+			for len(queue) > 0 && !queue[0].at.After(now) {
+				entry := heap.Pop(&queue).(*deadline)
+				delete(entries, entry.key)
+				entry.fire()
+			}
+		}
+
+		if armed && (len(queue) == 0 || !queue[0].at.Equal(armedAt)) {
+			// The head of the queue changed.
+			if !timer.Stop() {
+				<-timer.C()
+			}
+			armed = false
+		}
+		if !armed && len(queue) > 0 {
+			armedAt = queue[0].at
+			if timer == nil {
+				timer = s.clock.NewTimer(armedAt.Sub(s.clock.Now()))
+			} else {
+				timer.Reset(armedAt.Sub(s.clock.Now()))
+			}
+			armed = true
+		}
+	}
+}
+
+type deadline struct {
+	key   any
+	at    time.Time
+	fire  func()
+	index int
+}
+
+// deadlineQueue implements heap.Interface, the earliest deadline first.
+type deadlineQueue []*deadline
+
+func (q deadlineQueue) Len() int {
+	return len(q)
+}
+
+func (q deadlineQueue) Less(i, j int) bool {
+	return q[i].at.Before(q[j].at)
+}
+
+func (q deadlineQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *deadlineQueue) Push(x any) {
+	entry := x.(*deadline)
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+
+func (q *deadlineQueue) Pop() any {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return entry
+}
@@ -0,0 +1,106 @@
+// This file is part of GoforPomodoro.
+//
+// GoforPomodoro is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// GoforPomodoro is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with GoforPomodoro.  If not, see <http://www.gnu.org/licenses/>.
+
+package sessionmanager
+
+import (
+	"GoforPomodoro/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestSchedulerFiresInDeadlineOrder(t *testing.T) {
+	start := time.Date(2022, time.October, 17, 9, 0, 0, 0, time.UTC)
+	clock := domain.NewFakeClock(start)
+	scheduler := NewScheduler(clock)
+
+	fired := make(chan string, 10)
+	schedule := func(key string, after time.Duration) {
+		scheduler.Schedule(key, start.Add(after), func() { fired <- key })
+	}
+
+	schedule("c", 3*time.Minute)
+	schedule("a", 1*time.Minute)
+	schedule("b", 2*time.Minute)
+	schedule("d", 4*time.Minute)
+
+	// Scheduling again replaces the previous deadline.
+	schedule("a", 5*time.Minute)
+	scheduler.Unschedule("d")
+
+	<-clock.Sleepers(1)
+	clock.Advance(10 * time.Minute)
+
+	expected := []string{"b", "c", "a"}
+	for _, key := range expected {
+		select {
+		case got := <-fired:
+			if got != key {
+				t.Fatalf("expected %s to fire, instead %s fired", key, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s never fired", key)
+		}
+	}
+
+	select {
+	case got := <-fired:
+		t.Fatalf("%s should not have fired", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestSchedulerFollowsTheEarliestDeadline(t *testing.T) {
+	start := time.Date(2022, time.October, 17, 9, 0, 0, 0, time.UTC)
+	clock := domain.NewFakeClock(start)
+	scheduler := NewScheduler(clock)
+
+	fired := make(chan string, 10)
+	expectFired := func(expected string) {
+		t.Helper()
+		select {
+		case got := <-fired:
+			if got != expected {
+				t.Fatalf("expected %s to fire, instead %s fired", expected, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s never fired", expected)
+		}
+	}
+
+	scheduler.Schedule("a", start.Add(5*time.Minute), func() { fired <- "a" })
+	<-clock.Sleepers(1)
+	clock.Advance(2 * time.Minute)
+
+	// A deadline earlier than the one waited for.
+	scheduler.Schedule("b", start.Add(3*time.Minute), func() { fired <- "b" })
+	// A deadline removed while it is waited for.
+	scheduler.Schedule("c", start.Add(150*time.Second), func() { fired <- "c" })
+	scheduler.Unschedule("c")
+
+	<-clock.Sleepers(1)
+	clock.Advance(time.Minute)
+	expectFired("b")
+
+	<-clock.Sleepers(1)
+	clock.Advance(2 * time.Minute)
+	expectFired("a")
+
+	select {
+	case got := <-fired:
+		t.Fatalf("%s should not have fired", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
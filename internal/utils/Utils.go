@@ -19,40 +19,10 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 )
 
-func NiceTimeFormatting64(seconds int64) string {
-	if seconds > 60*60 {
-		// >1 hour
-		minutes := int(math.Ceil(float64(seconds) / 60.0))
-		hours := int(math.Floor(float64(minutes) / 60.0))
-
-		minutes = minutes % 60
-
-		var hoursTxt string
-		if hours == 1 {
-			hoursTxt = "hour"
-		} else {
-			hoursTxt = "hours"
-		}
-
-		return fmt.Sprintf("%d %s %d minutes", hours, hoursTxt, minutes)
-	} else if seconds > 60 {
-		// >1 minute
-
-		minutes := int(math.Ceil(float64(seconds) / 60.0))
-		return fmt.Sprintf("%d minutes", minutes)
-	} else {
-		// <=1 minute
-		return fmt.Sprintf("%d seconds", seconds)
-	}
-}
-
-func NiceTimeFormatting(seconds int) string {
-	return NiceTimeFormatting64(int64(seconds))
-}
-
 func In[T comparable](element T, array []T) bool {
 	found := false
 	for _, v := range array {
@@ -104,6 +74,64 @@ func TimePtr(t time.Time) *time.Time {
 	return &t
 }
 
+// StartOfDay returns the midnight that opened the day of `t` (in the location
+// of `t`).
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns the midnight that opened the week of `t` (in the
+// location of `t`). Weeks start on Monday.
+func StartOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return StartOfDay(t).AddDate(0, 0, -daysSinceMonday)
+}
+
+// ShortDuration Print a duration (in SECONDS) in compact form, e.g. "25m",
+// "1h30m" or "45s".
+func ShortDuration(seconds int64) string {
+	if seconds <= 0 {
+		return "0m"
+	}
+
+	var sb strings.Builder
+	if hours := seconds / 3600; hours > 0 {
+		sb.WriteString(fmt.Sprintf("%dh", hours))
+	}
+	if minutes := seconds % 3600 / 60; minutes > 0 {
+		sb.WriteString(fmt.Sprintf("%dm", minutes))
+	}
+	if seconds%60 > 0 {
+		sb.WriteString(fmt.Sprintf("%ds", seconds%60))
+	}
+	return sb.String()
+}
+
+// ProgressBar Draw a bar of `width` blocks, filled in proportion to how much
+// of `total` is `done`.
+func ProgressBar(done, total float64, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = int(math.Round(done / total * float64(width)))
+	}
+	if filled < 0 {
+		filled = 0
+	} else if filled > width {
+		filled = width
+	}
+
+	var sb strings.Builder
+	for i := 0; i < width; i++ {
+		if i < filled {
+			sb.WriteString("▓")
+		} else {
+			sb.WriteString("░")
+		}
+	}
+	return sb.String()
+}
+
 type Pair[T, U any] struct {
 	First  T
 	Second U
@@ -17,6 +17,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 )
 
 func TestIsCapitalizedLetter(t *testing.T) {
@@ -72,29 +73,56 @@ func TestAfterRemoveEl(t *testing.T) {
 	}
 }
 
-func TestNiceTimeFormatting(t *testing.T) {
-	{
-		ok := NiceTimeFormatting(10) == "10 seconds"
-		if !ok {
-			t.Fatalf("NiceTimeFormatting(10) != \"10 seconds\" ")
-		}
+func TestStartOfWeek(t *testing.T) {
+	// Sunday, 16th October 2022
+	sunday := time.Date(2022, time.October, 16, 18, 30, 0, 0, time.UTC)
+	monday := time.Date(2022, time.October, 10, 0, 0, 0, 0, time.UTC)
+
+	if !StartOfWeek(sunday).Equal(monday) {
+		t.Fatalf("StartOfWeek(%v) should be %v, instead it is %v", sunday, monday, StartOfWeek(sunday))
+	}
+	if !StartOfWeek(monday).Equal(monday) {
+		t.Fatalf("StartOfWeek(%v) should be %v, instead it is %v", monday, monday, StartOfWeek(monday))
+	}
+	if !StartOfDay(sunday).Equal(time.Date(2022, time.October, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("StartOfDay(%v) is %v", sunday, StartOfDay(sunday))
 	}
+}
 
-	{
-		lhs := "2 minutes"
-		rhs := NiceTimeFormatting(100)
-		ok := lhs == rhs
-		if !ok {
-			t.Fatalf("error second check. Should be %s, instead it is %s", lhs, rhs)
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		done, total float64
+		expected    string
+	}{
+		{0, 10, "░░░░░"},
+		{5, 10, "▓▓▓░░"},
+		{10, 10, "▓▓▓▓▓"},
+		{12, 10, "▓▓▓▓▓"},
+		{-1, 10, "░░░░░"},
+		{1, 0, "░░░░░"},
+	}
+	for _, test := range tests {
+		if bar := ProgressBar(test.done, test.total, 5); bar != test.expected {
+			t.Fatalf("ProgressBar(%v, %v, 5) should be %s, instead it is %s", test.done, test.total, test.expected, bar)
 		}
 	}
+}
 
-	{
-		lhs := "1 hour 55 minutes"
-		rhs := NiceTimeFormatting(115 * 60)
-		ok := lhs == rhs
-		if !ok {
-			t.Fatalf("error third check. Should be %s, instead it is %s", lhs, rhs)
+func TestShortDuration(t *testing.T) {
+	tests := []struct {
+		seconds  int64
+		expected string
+	}{
+		{0, "0m"},
+		{45, "45s"},
+		{25 * 60, "25m"},
+		{90 * 60, "1h30m"},
+		{2 * 60 * 60, "2h"},
+		{60*60 + 30, "1h30s"},
+	}
+	for _, test := range tests {
+		if got := ShortDuration(test.seconds); got != test.expected {
+			t.Errorf("ShortDuration(%d) = %q, expected %q", test.seconds, got, test.expected)
 		}
 	}
 }